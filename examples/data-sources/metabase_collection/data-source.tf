@@ -0,0 +1,10 @@
+# A top-level collection.
+data "metabase_collection" "finance" {
+  name = "Finance"
+}
+
+# A collection nested under it ("Finance / Reports").
+data "metabase_collection" "finance_reports" {
+  name      = "Reports"
+  parent_id = data.metabase_collection.finance.id
+}
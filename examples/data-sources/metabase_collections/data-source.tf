@@ -0,0 +1,4 @@
+# Every collection under "Finance", at any depth.
+data "metabase_collections" "finance" {
+  parent_id = data.metabase_collection.finance.id
+}
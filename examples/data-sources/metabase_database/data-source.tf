@@ -0,0 +1,4 @@
+# A database connection created by another team.
+data "metabase_database" "warehouse" {
+  name = "Data Warehouse"
+}
@@ -0,0 +1,4 @@
+# Every PostgreSQL connection.
+data "metabase_databases" "postgres" {
+  engine = "postgres"
+}
@@ -0,0 +1,8 @@
+# The built-in groups, without hard-coding their ids.
+data "metabase_permission_group" "all_users" {
+  name = "All Users"
+}
+
+data "metabase_permission_group" "administrators" {
+  name = "Administrators"
+}
@@ -0,0 +1,4 @@
+# Every group whose name starts with "Team ".
+data "metabase_permission_groups" "teams" {
+  name_regex = "^Team "
+}
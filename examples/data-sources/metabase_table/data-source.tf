@@ -0,0 +1,5 @@
+data "metabase_table" "orders" {
+  database_id = data.metabase_database.warehouse.id
+  schema      = "public"
+  name        = "orders"
+}
@@ -0,0 +1,3 @@
+data "metabase_user" "jane" {
+  email = "jane.doe@example.com"
+}
@@ -0,0 +1,12 @@
+# Every active user of the example.com domain...
+data "metabase_users" "example_com" {
+  email_regex = "@example\\.com$"
+}
+
+# ...added to the "Employees" group.
+resource "metabase_user_permission_group_membership" "employees" {
+  for_each = { for u in data.metabase_users.example_com.users : u.email => u.id }
+
+  user_id             = each.value
+  permission_group_id = metabase_permission_group.employees.id
+}
@@ -2,3 +2,6 @@ provider "metabase" {
   host    = "https://metabase.example.com"
   api_key = "your_api_key"
 }
+
+# Alternatively, leave the block empty and set METABASE_HOST plus
+# METABASE_API_KEY (or METABASE_USERNAME and METABASE_PASSWORD) in the environment.
@@ -0,0 +1,10 @@
+resource "metabase_permission_group" "platform" {
+  name = "Platform"
+}
+
+# The platform team can use the monitoring tools (audit, troubleshooting)
+# without being admins.
+resource "metabase_application_permission" "platform_monitoring" {
+  group_id = metabase_permission_group.platform.id
+  area     = "monitoring"
+}
@@ -0,0 +1,30 @@
+# A native SQL question in the "Analytics" collection.
+resource "metabase_card" "daily_orders" {
+  name          = "Daily orders"
+  description   = "Orders per day over the last 30 days"
+  collection_id = metabase_collection.analytics.id
+  display       = "line"
+  dataset_query = jsonencode({
+    database = tonumber(metabase_database.sales.id)
+    type     = "native"
+    native = {
+      query = "SELECT created_at::date AS day, count(*) FROM orders WHERE created_at > now() - interval '30 days' GROUP BY 1 ORDER BY 1"
+    }
+  })
+  visualization_settings = jsonencode({
+    "graph.dimensions" = ["day"]
+    "graph.metrics"    = ["count"]
+  })
+}
+
+# A model built with the query builder on table 42.
+resource "metabase_card" "orders_model" {
+  name          = "Orders"
+  collection_id = metabase_collection.analytics.id
+  type          = "model"
+  dataset_query = jsonencode({
+    database = tonumber(metabase_database.sales.id)
+    type     = "query"
+    query    = { "source-table" = 42 }
+  })
+}
@@ -0,0 +1,12 @@
+resource "metabase_permission_group" "analysts" {
+  name = "Analysts"
+}
+
+# Analysts' queries on the warehouse run as the PostgreSQL role in their
+# "db_role" user attribute, so row-level security policies of the database
+# apply.
+resource "metabase_connection_impersonation" "analysts_warehouse" {
+  group_id    = metabase_permission_group.analysts.id
+  database_id = metabase_database.warehouse.id
+  attribute   = "db_role"
+}
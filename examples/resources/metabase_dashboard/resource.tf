@@ -0,0 +1,43 @@
+# A dashboard with a category filter and two tabs.
+resource "metabase_dashboard" "sales" {
+  name          = "Sales"
+  description   = "Managed by Terraform"
+  collection_id = metabase_collection.reports.id
+
+  parameters = jsonencode([{
+    id   = "a1b2c3d4"
+    name = "Category"
+    slug = "category"
+    type = "string/="
+  }])
+
+  tabs = [
+    { name = "Overview" },
+    { name = "Details" },
+  ]
+
+  dashcards = [
+    {
+      card_id = metabase_card.revenue.id
+      tab     = "Overview"
+      row     = 0
+      col     = 0
+      size_x  = 12
+      size_y  = 6
+      parameter_mappings = jsonencode([{
+        parameter_id = "a1b2c3d4"
+        card_id      = tonumber(metabase_card.revenue.id)
+        target       = ["dimension", ["field", 34, null]]
+      }])
+    },
+    {
+      card_id                = metabase_card.orders.id
+      tab                    = "Details"
+      row                    = 0
+      col                    = 0
+      size_x                 = 24
+      size_y                 = 8
+      visualization_settings = jsonencode({ "card.title" = "All orders" })
+    },
+  ]
+}
@@ -1,15 +1,32 @@
-# A Postgres database connection managed by Terraform.
+# A Postgres database connection managed by Terraform. The typed block sets the
+# engine and knows which keys Metabase redacts, so drift is detected on the rest.
 resource "metabase_database" "sales" {
-  name   = "Analytics Postgres"
-  engine = "postgres"
-  details = jsonencode({
+  name = "Analytics Postgres"
+  postgres = {
     host     = "db.example.com"
     port     = 5432
     dbname   = "analytics"
     user     = "metabase"
-    password = "..."
-    ssl      = false
-  })
+    ssl      = true
+    ssl_mode = "verify-full"
+  }
+
+  # The password is write-only: sent to Metabase, never stored in state
+  # (Terraform 1.11+). Bump the version to push a rotated password.
+  details_wo         = jsonencode({ password = var.analytics_db_password })
+  details_wo_version = 1
+
+  # Sync the schema nightly and scan filter values on Sunday mornings.
+  schedules = {
+    metadata_sync      = "0 0 2 * * ? *"
+    cache_field_values = "0 0 4 ? * SUN *"
+  }
+  auto_run_queries = false
+
+  # Test the connection during plan, and wait for the first sync so resources
+  # using its tables don't race it.
+  validate_connection = true
+  wait_for_sync       = true
 
   # Metabase hard-deletes a database and ALL content built on it. deletion_protection
   # (default true) makes the provider refuse to delete it; set false + apply first.
@@ -20,3 +37,18 @@ resource "metabase_database" "sales" {
     prevent_destroy = true
   }
 }
+
+# Engines without a typed block take raw details; list the keys Metabase
+# redacts to detect drift on the others.
+resource "metabase_database" "legacy" {
+  name   = "Legacy SQL Server"
+  engine = "sqlserver"
+  details = jsonencode({
+    host     = "mssql.example.com"
+    port     = 1433
+    db       = "legacy"
+    user     = "metabase"
+    password = "..."
+  })
+  redacted_attributes = ["password"]
+}
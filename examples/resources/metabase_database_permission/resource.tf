@@ -5,3 +5,35 @@ resource "metabase_database_permission" "analysts_sales" {
   database_id    = metabase_database.sales.id
   create_queries = "query-builder" # "no" | "query-builder" | "query-builder-and-native"
 }
+
+# Enterprise (advanced permissions): the full row, written in one graph update.
+resource "metabase_database_permission" "finance_sales" {
+  group_id       = metabase_permission_group.finance.id
+  database_id    = metabase_database.sales.id
+  create_queries = "query-builder"
+  view_data      = "unrestricted"
+  data_model     = "none"
+  details        = "no"
+
+  download_schemas = {
+    public = "full"
+    hr     = "none"
+  }
+}
+
+# Granular access: the "Finance" group may only query two schemas of the
+# warehouse, and a single table of a third.
+resource "metabase_database_permission" "finance_warehouse" {
+  group_id    = metabase_permission_group.finance.id
+  database_id = metabase_database.warehouse.id
+
+  create_queries_schemas = {
+    finance  = { level = "query-builder" }
+    billing  = { level = "query-builder" }
+    shipping = {
+      tables = {
+        (data.metabase_table.shipments.id) = "query-builder"
+      }
+    }
+  }
+}
@@ -0,0 +1,33 @@
+data "metabase_table" "orders" {
+  database_id = metabase_database.warehouse.id
+  schema      = "public"
+  name        = "orders"
+}
+
+data "metabase_table" "customers" {
+  database_id = metabase_database.warehouse.id
+  schema      = "public"
+  name        = "customers"
+}
+
+# Adopt customers.id as-is, to reference its field id.
+resource "metabase_field_metadata" "customer_id" {
+  table_id = data.metabase_table.customers.id
+  name     = "id"
+}
+
+# Declare orders.customer_id as a foreign key to customers.id.
+resource "metabase_field_metadata" "order_customer" {
+  table_id           = data.metabase_table.orders.id
+  name               = "customer_id"
+  display_name       = "Customer"
+  semantic_type      = "type/FK"
+  fk_target_field_id = metabase_field_metadata.customer_id.id
+}
+
+# Keep a column out of filters and results.
+resource "metabase_field_metadata" "order_card_number" {
+  table_id        = data.metabase_table.orders.id
+  name            = "card_number"
+  visibility_type = "sensitive"
+}
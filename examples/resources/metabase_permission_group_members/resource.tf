@@ -0,0 +1,13 @@
+# Everyone in the "Analysts" group. Any other member (added in the UI or by
+# another tool) is removed on the next apply.
+resource "metabase_permission_group_members" "analysts" {
+  group_id = metabase_permission_group.analysts.id
+
+  members = [
+    metabase_user.john_doe.id,
+    metabase_user.jane_doe.id,
+  ]
+
+  # Enterprise only: Jane can add and remove analysts.
+  group_managers = [metabase_user.jane_doe.id]
+}
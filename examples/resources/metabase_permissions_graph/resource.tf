@@ -0,0 +1,15 @@
+# Everything the "Analysts" group can access. Any other grant (made in the UI
+# or by another tool) is revoked on the next apply.
+resource "metabase_permissions_graph" "analysts" {
+  group_id = metabase_permission_group.analysts.id
+
+  databases = {
+    (metabase_database.warehouse.id) = "query-builder-and-native"
+    (metabase_database.crm.id)       = "query-builder"
+  }
+
+  collections = {
+    "root"                           = "read"
+    (metabase_collection.reports.id) = "write"
+  }
+}
@@ -0,0 +1,42 @@
+resource "metabase_permission_group" "customer_success" {
+  name = "Customer success"
+}
+
+data "metabase_table" "accounts" {
+  database_id = metabase_database.warehouse.id
+  schema      = "public"
+  name        = "accounts"
+}
+
+data "metabase_table" "invoices" {
+  database_id = metabase_database.warehouse.id
+  schema      = "public"
+  name        = "invoices"
+}
+
+# Adopt accounts.owner_email as-is, to reference its field id.
+resource "metabase_field_metadata" "account_owner" {
+  table_id = data.metabase_table.accounts.id
+  name     = "owner_email"
+}
+
+# Each member of the group only sees the accounts they own: the rows whose
+# owner_email equals their "email" user attribute.
+resource "metabase_sandbox" "accounts" {
+  group_id = metabase_permission_group.customer_success.id
+  table_id = data.metabase_table.accounts.id
+  attribute_remappings = {
+    email = { field_id = metabase_field_metadata.account_owner.id }
+  }
+}
+
+# Or show a saved question instead of the table, filtered by its "region"
+# variable.
+resource "metabase_sandbox" "invoices" {
+  group_id = metabase_permission_group.customer_success.id
+  table_id = data.metabase_table.invoices.id
+  card_id  = metabase_card.regional_invoices.id
+  attribute_remappings = {
+    region = { template_tag = "region" }
+  }
+}
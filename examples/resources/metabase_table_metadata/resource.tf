@@ -0,0 +1,17 @@
+# Curate a synced table: Terraform adopts it and never creates or deletes it.
+resource "metabase_table_metadata" "orders" {
+  database_id  = metabase_database.warehouse.id
+  schema       = "public"
+  name         = "orders"
+  display_name = "Orders"
+  description  = "One row per order, including refunded ones."
+  entity_type  = "entity/TransactionTable"
+}
+
+# Hide a staging table from the query builder.
+resource "metabase_table_metadata" "orders_staging" {
+  database_id     = metabase_database.warehouse.id
+  schema          = "public"
+  name            = "orders_staging"
+  visibility_type = "technical"
+}
@@ -3,3 +3,15 @@ resource "metabase_user" "john_doe" {
   first_name = "John"
   last_name  = "Doe"
 }
+
+# An admin whose sandboxes filter on account_id, with a German interface.
+resource "metabase_user" "jane_roe" {
+  email        = "jane@roe.com"
+  first_name   = "Jane"
+  last_name    = "Roe"
+  is_superuser = true
+  locale       = "de"
+  login_attributes = {
+    account_id = "42"
+  }
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func NewApplicationPermission() resource.Resource {
+	applicationPermission := &ApplicationPermission{}
+
+	baseResource := &BaseResource{
+		TypeName: "application_permission",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			applicationPermission.repository = repositories.NewApplicationPermissionRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Enterprise. Access of one permission group to one application area (an edge of the Metabase application permissions graph), without making the group admins. Removing the resource revokes access (sets it to \"no\"), so an area the group already has can't be created: import it instead. Requires the `advanced_permissions` feature; planning fails on instances without it.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Composite id \"<group_id>:<area>\"",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"group_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the permission group",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"area": schema.StringAttribute{
+						MarkdownDescription: "Area granted: \"setting\" (admin settings), \"monitoring\" (tools, audit and troubleshooting) or \"subscription\" (creating dashboard subscriptions and alerts).",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+						Validators:          []validator.String{OneOfValidator("setting", "monitoring", "subscription")},
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.ApplicationPermissionTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			groupId, area := plan.GroupId.ValueString(), plan.Area.ValueString()
+			// Adopting a grant silently would revoke it on destroy; return an import hint.
+			granted, err := applicationPermission.repository.Get(ctx, groupId, area)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to get application permission: %s", err))
+				return
+			}
+			if granted {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf(
+					"group %s already has %q access; Terraform will not adopt it, as destroying it would revoke access granted outside Terraform. Import it instead: `terraform import metabase_application_permission.<name> %s:%s`",
+					groupId, area, groupId, area,
+				))
+				return
+			}
+
+			err = applicationPermission.repository.Set(ctx, groupId, area, "yes")
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to grant application permission: %s", err))
+				return
+			}
+
+			plan.Id = idOf(groupId, area)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.ApplicationPermissionTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			groupId, area, err := splitEdgeID(state.Id.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Read Error", err.Error())
+				return
+			}
+
+			granted, err := applicationPermission.repository.Get(ctx, groupId, area)
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get application permission: %s", err))
+				return
+			}
+			// Revoked out-of-band, or the group is gone.
+			if !granted {
+				resp.State.RemoveResource(ctx)
+				return
+			}
+
+			result := terraform.ApplicationPermissionTerraformModel{
+				Id:      idOf(groupId, area),
+				GroupId: stringValue(groupId),
+				Area:    stringValue(area),
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			// Every attribute requires replacement.
+			var plan terraform.ApplicationPermissionTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			var state terraform.ApplicationPermissionTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			err := applicationPermission.repository.Set(ctx, state.GroupId.ValueString(), state.Area.ValueString(), "no")
+			if err != nil {
+				resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("Unable to revoke application permission: %s", err))
+			}
+		},
+	}
+
+	applicationPermission.BaseResource = baseResource
+
+	return applicationPermission
+}
+
+var _ resource.ResourceWithModifyPlan = &ApplicationPermission{}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. It fails the plan on
+// instances without application permissions (OSS, or a license without
+// advanced permissions).
+func (a *ApplicationPermission) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || a.repository == nil {
+		return // destroy, or the provider is not configured yet
+	}
+	if err := a.repository.Supported(); err != nil {
+		resp.Diagnostics.AddError("Unsupported Feature", fmt.Sprintf("%s. Application permissions are a Metabase Enterprise feature.", err))
+	}
+}
+
+// ApplicationPermission defines the resource implementation.
+type ApplicationPermission struct {
+	*BaseResource
+	repository *repositories.ApplicationPermissionRepository
+}
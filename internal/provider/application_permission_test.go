@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testAccCheckApplicationPermissionRevoked(s *terraform.State) error {
+	repo := repositories.NewApplicationPermissionRepository(newTestMetabaseClient())
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metabase_application_permission" {
+			continue
+		}
+		granted, err := repo.Get(context.Background(), rs.Primary.Attributes["group_id"], rs.Primary.Attributes["area"])
+		if err != nil {
+			return err
+		}
+		if granted {
+			return fmt.Errorf("application permission %s still granted after destroy", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func TestAccApplicationPermissionResource(t *testing.T) {
+	name := fmt.Sprintf("Test platform group %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckTokenFeature(t, "advanced_permissions")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckApplicationPermissionRevoked,
+		Steps: []resource.TestStep{
+			// Two edges in one apply exercise the revision retry (concurrency).
+			{
+				Config: testAccApplicationPermissionConfig(name, "setting"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_application_permission.monitoring", "area", "monitoring"),
+					resource.TestCheckResourceAttr("metabase_application_permission.other", "area", "setting"),
+				),
+			},
+			{
+				ResourceName:      "metabase_application_permission.monitoring",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Another area is a new edge: the old one is revoked. New groups may
+			// have "subscription" by default: revoke it first, or create fails.
+			{
+				PreConfig: func() {
+					client := newTestMetabaseClient()
+					group, err := repositories.NewPermissionGroupRepository(client).FindByName(context.Background(), name)
+					if err != nil || group == nil {
+						t.Fatalf("unable to look up group %q: %v", name, err)
+					}
+					if err := repositories.NewApplicationPermissionRepository(client).Set(context.Background(), strconv.Itoa(group.Id), "subscription", "no"); err != nil {
+						t.Fatalf("unable to revoke subscription: %s", err)
+					}
+				},
+				Config: testAccApplicationPermissionConfig(name, "subscription"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_application_permission.other", plancheck.ResourceActionReplace),
+						plancheck.ExpectResourceAction("metabase_application_permission.monitoring", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("metabase_application_permission.other", "area", "subscription"),
+			},
+		},
+	})
+}
+
+// TestAccApplicationPermissionResource_existingGrantErrors asserts that an
+// area the group already has (All Users has "subscription" by default) is not
+// adopted, since destroy would revoke it.
+func TestAccApplicationPermissionResource_existingGrantErrors(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckTokenFeature(t, "advanced_permissions")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+data "metabase_permission_group" "all_users" {
+  name = "All Users"
+}
+
+resource "metabase_application_permission" "test" {
+  group_id = data.metabase_permission_group.all_users.id
+  area     = "subscription"
+}
+`,
+				ExpectError: regexp.MustCompile("already has"),
+			},
+		},
+	})
+}
+
+func testAccApplicationPermissionConfig(groupName, area string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_permission_group" "test" {
+  name = %[1]q
+}
+
+resource "metabase_application_permission" "monitoring" {
+  group_id = metabase_permission_group.test.id
+  area     = "monitoring"
+}
+
+resource "metabase_application_permission" "other" {
+  group_id = metabase_permission_group.test.id
+  area     = %[2]q
+}
+`, groupName, area)
+}
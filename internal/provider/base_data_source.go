@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// Ensure BaseDataSource implements required interfaces.
+var _ datasource.DataSource = &BaseDataSource{}
+var _ datasource.DataSourceWithConfigure = &BaseDataSource{}
+
+// BaseDataSource provides common functionality for all data sources.
+type BaseDataSource struct {
+	// TypeName is the name of the data source type (e.g., "collection")
+	TypeName string
+
+	// ConfigureRepository is a function that configures the repository for the data source
+	ConfigureRepository func(client *metabase.MetabaseAPIClient)
+
+	// GetSchema returns the schema for the data source
+	GetSchema func(ctx context.Context) schema.Schema
+
+	// ReadFunc reads the data source
+	ReadFunc func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse)
+}
+
+// Metadata implements datasource.DataSource.
+func (d *BaseDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.TypeName
+}
+
+// Schema implements datasource.DataSource.
+func (d *BaseDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = d.GetSchema(ctx)
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (d *BaseDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*metabase.MetabaseAPIClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *metabase.MetabaseAPIClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.ConfigureRepository(client)
+}
+
+// Read implements datasource.DataSource.
+func (d *BaseDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	d.ReadFunc(ctx, req, resp)
+}
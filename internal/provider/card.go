@@ -0,0 +1,225 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func NewCard() resource.Resource {
+	card := &Card{}
+
+	baseResource := &BaseResource{
+		TypeName: "card",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			card.repository = repositories.NewCardRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "A card is a saved question, model or metric. Its query and visualization settings are managed as JSON; Metabase's normalization of them (added keys, reordering) is not reported as drift. Removing the resource sends the card to the Trash (never a permanent delete).",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Card ID",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the card",
+						Required:            true,
+					},
+					"description": schema.StringAttribute{
+						MarkdownDescription: "Description of the card",
+						Optional:            true,
+					},
+					"collection_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the collection holding the card. Omit for the root collection (\"Our Analytics\").",
+						Optional:            true,
+					},
+					"display": schema.StringAttribute{
+						MarkdownDescription: "Visualization type, e.g. \"table\", \"scalar\", \"line\", \"bar\", \"pie\". Defaults to \"table\".",
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString("table"),
+					},
+					"type": schema.StringAttribute{
+						MarkdownDescription: "Kind of card: \"question\" (default), \"model\" or \"metric\" (Metabase 51 or later).",
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString("question"),
+						Validators:          []validator.String{OneOfValidator("question", "model", "metric")},
+					},
+					"dataset_query": schema.StringAttribute{
+						MarkdownDescription: "The query as a JSON object (use jsonencode), in the shape of Metabase's `dataset_query`, e.g. `{ database = 1, type = \"native\", native = { query = \"SELECT 1\" } }`. Keys Metabase adds when normalizing the query are ignored.",
+						CustomType:          terraform.JSONType{},
+						Required:            true,
+						Validators:          []validator.String{JSONValidator()},
+					},
+					"visualization_settings": schema.StringAttribute{
+						MarkdownDescription: "Visualization settings as a JSON object (use jsonencode). Defaults to `{}`.",
+						CustomType:          terraform.JSONType{},
+						Optional:            true,
+						Computed:            true,
+						Validators:          []validator.String{JSONValidator()},
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"archived": schema.BoolAttribute{
+						MarkdownDescription: "Whether the card is in the Trash. Set true to send it to the Trash (recoverable) while keeping it managed. Removing the resource also sends it to the Trash.",
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.CardTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if plan.Archived.ValueBool() {
+				resp.Diagnostics.AddError("Invalid value", "A card can't be created with archived=true")
+				return
+			}
+
+			input, err := cardFromModel(plan)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", err.Error())
+				return
+			}
+
+			createResponse, err := card.repository.Create(ctx, input)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to create card: %s", err))
+				return
+			}
+
+			result, err := terraform.CreateCardTerraformModelFromDTO(createResponse, plan)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to read created card: %s", err))
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.CardTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			getResponse, err := card.repository.Get(ctx, state.Id.ValueString())
+			if err != nil {
+				// Deleted out-of-band (404): drop from state so it's recreated.
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.State.RemoveResource(ctx)
+					return
+				}
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get card: %s", err))
+				return
+			}
+
+			result, err := terraform.CreateCardTerraformModelFromDTO(getResponse, state)
+			if err != nil {
+				resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to reconcile card: %s", err))
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.CardTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			input, err := cardFromModel(plan)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", err.Error())
+				return
+			}
+
+			_, err = card.repository.Update(ctx, plan.Id.ValueString(), input)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update card: %s", err))
+				return
+			}
+
+			// The new state is not read from the API
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			var state terraform.CardTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// Destroy = archive to Trash (recoverable), never a permanent delete.
+			err := card.repository.Archive(ctx, state.Id.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Archive Error", fmt.Sprintf("Unable to archive card: %s", err))
+				return
+			}
+		},
+	}
+
+	card.BaseResource = baseResource
+
+	return card
+}
+
+// cardFromModel converts the plan into the API shape. visualization_settings
+// is unknown on a create that omits it, and then sent as Metabase's default {}.
+func cardFromModel(plan terraform.CardTerraformModel) (*dtos.CardDTO, error) {
+	input := &dtos.CardDTO{
+		Name:         plan.Name.ValueString(),
+		Description:  plan.Description.ValueStringPointer(),
+		Display:      plan.Display.ValueString(),
+		Type:         plan.Type.ValueString(),
+		DatasetQuery: json.RawMessage(plan.DatasetQuery.ValueString()),
+		Archived:     plan.Archived.ValueBool(),
+	}
+	if !plan.CollectionId.IsNull() {
+		collectionId, err := strconv.Atoi(plan.CollectionId.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("invalid collection_id %q: must be a numeric collection ID", plan.CollectionId.ValueString())
+		}
+		input.CollectionId = &collectionId
+	}
+	if plan.VisualizationSettings.IsNull() || plan.VisualizationSettings.IsUnknown() {
+		input.VisualizationSettings = json.RawMessage("{}")
+	} else {
+		input.VisualizationSettings = json.RawMessage(plan.VisualizationSettings.ValueString())
+	}
+	return input, nil
+}
+
+// Card defines the resource implementation.
+type Card struct {
+	*BaseResource
+	repository *repositories.CardRepository
+}
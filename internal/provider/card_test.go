@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccCheckCardArchived asserts that destroy sends cards to the Trash.
+func testAccCheckCardArchived(s *terraform.State) error {
+	repo := repositories.NewCardRepository(newTestMetabaseClient())
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metabase_card" {
+			continue
+		}
+		c, err := repo.Get(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("card %s get failed after destroy: %w", rs.Primary.ID, err)
+		}
+		if !c.Archived {
+			return fmt.Errorf("card %s is not archived after destroy", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+// TestAccCardResource covers create, import, in-place edits and that a
+// re-formatted (semantically equal) query plans no change.
+func TestAccCardResource(t *testing.T) {
+	name := fmt.Sprintf("Test card %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCardArchived,
+		Steps: []resource.TestStep{
+			// Create and Read. Metabase normalizes the query; the next plan must be empty.
+			{
+				Config: testAccCardResourceConfig(name, "SELECT 1", "question"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_card.test", "name", name),
+					resource.TestCheckResourceAttr("metabase_card.test", "type", "question"),
+					resource.TestCheckResourceAttr("metabase_card.test", "display", "scalar"),
+					resource.TestCheckResourceAttrPair("metabase_card.test", "collection_id", "metabase_collection.test", "id"),
+					resource.TestCheckResourceAttrSet("metabase_card.test", "id"),
+				),
+			},
+			// ImportState. The JSON attributes are imported in Metabase's normalized form.
+			{
+				ResourceName:            "metabase_card.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"dataset_query", "visualization_settings"},
+			},
+			// Change the query and turn it into a model in-place.
+			{
+				Config: testAccCardResourceConfig(name, "SELECT 2", "model"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_card.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("metabase_card.test", "type", "model"),
+			},
+		},
+	})
+}
+
+func testAccCardResourceConfig(name, query, cardType string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                = "%[1]s db"
+  engine              = "postgres"
+  deletion_protection = false
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+
+resource "metabase_collection" "test" {
+  name = "%[1]s collection"
+}
+
+resource "metabase_card" "test" {
+  name          = "%[1]s"
+  description   = "Managed by Terraform"
+  collection_id = metabase_collection.test.id
+  display       = "scalar"
+  type          = "%[3]s"
+  dataset_query = jsonencode({
+    database = tonumber(metabase_database.test.id)
+    type     = "native"
+    native   = { query = "%[2]s" }
+  })
+}
+`, name, query, cardType)
+}
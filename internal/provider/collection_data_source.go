@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+func NewCollectionDataSource() datasource.DataSource {
+	collection := &CollectionDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "collection",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			collection.repository = repositories.NewCollectionRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Looks up an existing collection by id, or by name and parent (its place in the collection tree). Lookups by name skip personal collections and the Trash.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						MarkdownDescription: "Collection ID. Set either id, or name (and optionally parent_id).",
+						Optional:            true,
+						Computed:            true,
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Exact name of the collection",
+						Optional:            true,
+						Computed:            true,
+					},
+					"parent_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the parent collection. When looking up by name, omit it to search the top level (\"Our Analytics\").",
+						Optional:            true,
+						Computed:            true,
+					},
+					"archived": schema.BoolAttribute{
+						MarkdownDescription: "Whether the collection is in the Trash",
+						Computed:            true,
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.CollectionTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if config.Id.IsNull() == config.Name.IsNull() || !config.Id.IsNull() && !config.ParentId.IsNull() {
+				resp.Diagnostics.AddError("Invalid lookup", "Set either id, or name (and optionally parent_id).")
+				return
+			}
+
+			var found *dtos.CollectionDTO
+			var err error
+			if !config.Id.IsNull() {
+				found, err = collection.repository.Get(ctx, config.Id.ValueString())
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No collection with id %s", config.Id.ValueString()))
+					return
+				}
+			} else {
+				found, err = collection.repository.FindByName(ctx, config.Name.ValueString(), config.ParentId.ValueStringPointer())
+				if err == nil && found == nil {
+					where := "at the top level"
+					if !config.ParentId.IsNull() {
+						where = fmt.Sprintf("in collection %s", config.ParentId.ValueString())
+					}
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No collection named %q %s", config.Name.ValueString(), where))
+					return
+				}
+			}
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get collection: %s", err))
+				return
+			}
+
+			result := terraform.CreateCollectionTerraformModelFromDTO(found)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+	}
+
+	collection.BaseDataSource = baseDataSource
+
+	return collection
+}
+
+// CollectionDataSource defines the data source implementation.
+type CollectionDataSource struct {
+	*BaseDataSource
+	repository *repositories.CollectionRepository
+}
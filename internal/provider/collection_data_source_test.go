@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccCollectionDataSource looks up a nested collection by name and parent,
+// and a top-level one by name.
+func TestAccCollectionDataSource(t *testing.T) {
+	name := fmt.Sprintf("Test collection ds %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_collection" "parent" {
+  name = "%[1]s"
+}
+
+resource "metabase_collection" "child" {
+  name      = "%[1]s child"
+  parent_id = metabase_collection.parent.id
+}
+
+data "metabase_collection" "parent" {
+  name = metabase_collection.parent.name
+}
+
+data "metabase_collection" "child" {
+  name      = metabase_collection.child.name
+  parent_id = metabase_collection.parent.id
+}
+`, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.metabase_collection.parent", "id", "metabase_collection.parent", "id"),
+					resource.TestCheckNoResourceAttr("data.metabase_collection.parent", "parent_id"),
+					resource.TestCheckResourceAttrPair("data.metabase_collection.child", "id", "metabase_collection.child", "id"),
+					resource.TestCheckResourceAttr("data.metabase_collection.child", "archived", "false"),
+				),
+			},
+		},
+	})
+}
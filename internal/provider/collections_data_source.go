@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func NewCollectionsDataSource() datasource.DataSource {
+	collections := &CollectionsDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "collections",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			collections.repository = repositories.NewCollectionRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Lists collections, optionally limited to a subtree and filtered by name. Collections in the Trash are never listed.",
+				Attributes: map[string]schema.Attribute{
+					"parent_id": schema.StringAttribute{
+						MarkdownDescription: "Only list collections under this one, at any depth (the collection itself is not listed).",
+						Optional:            true,
+					},
+					"name_regex": schema.StringAttribute{
+						MarkdownDescription: "Only list collections whose name matches this regular expression.",
+						Optional:            true,
+						Validators:          []validator.String{RegexValidator()},
+					},
+					"include_personal": schema.BoolAttribute{
+						MarkdownDescription: "Also list personal collections (and their sub-collections). Defaults to false.",
+						Optional:            true,
+					},
+					"collections": schema.ListNestedAttribute{
+						MarkdownDescription: "Matching collections, ordered by ID",
+						Computed:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									MarkdownDescription: "Collection ID",
+									Computed:            true,
+								},
+								"name": schema.StringAttribute{
+									MarkdownDescription: "Name of the collection",
+									Computed:            true,
+								},
+								"parent_id": schema.StringAttribute{
+									MarkdownDescription: "ID of the parent collection (null at the top level)",
+									Computed:            true,
+								},
+								"archived": schema.BoolAttribute{
+									MarkdownDescription: "Whether the collection is in the Trash (always false here)",
+									Computed:            true,
+								},
+							},
+						},
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.CollectionsDataSourceTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			nameRegex, err := optionalRegex(config.NameRegex)
+			if err != nil {
+				resp.Diagnostics.AddError("Invalid name_regex", err.Error())
+				return
+			}
+
+			list, err := collections.repository.List(ctx)
+			if err != nil {
+				resp.Diagnostics.AddError("List Error", fmt.Sprintf("Unable to list collections: %s", err))
+				return
+			}
+			sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+
+			// Sub-collections of a personal collection have no owner themselves:
+			// recognise them by their top-level ancestor.
+			personal := map[string]bool{}
+			for _, c := range list {
+				if c.PersonalOwnerId != nil {
+					personal[strconv.Itoa(c.Id)] = true
+				}
+			}
+
+			config.Collections = []terraform.CollectionTerraformModel{}
+			for i, c := range list {
+				// location is the ancestor chain, e.g. "/10/24/".
+				ancestors := strings.Split(strings.Trim(c.Location, "/"), "/")
+				if !config.IncludePersonal.ValueBool() && (c.PersonalOwnerId != nil || personal[ancestors[0]]) {
+					continue
+				}
+				if !config.ParentId.IsNull() && !strings.Contains(c.Location, "/"+config.ParentId.ValueString()+"/") {
+					continue
+				}
+				if matchesRegex(nameRegex, c.Name) {
+					config.Collections = append(config.Collections, terraform.CreateCollectionTerraformModelFromDTO(&list[i]))
+				}
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+		},
+	}
+
+	collections.BaseDataSource = baseDataSource
+
+	return collections
+}
+
+// CollectionsDataSource defines the data source implementation.
+type CollectionsDataSource struct {
+	*BaseDataSource
+	repository *repositories.CollectionRepository
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccCollectionsDataSource lists a subtree, at any depth.
+func TestAccCollectionsDataSource(t *testing.T) {
+	name := fmt.Sprintf("Test collections ds %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_collection" "top" {
+  name = "%[1]s"
+}
+
+resource "metabase_collection" "child" {
+  name      = "%[1]s child"
+  parent_id = metabase_collection.top.id
+}
+
+resource "metabase_collection" "grandchild" {
+  name      = "%[1]s grandchild"
+  parent_id = metabase_collection.child.id
+}
+
+data "metabase_collections" "subtree" {
+  parent_id  = metabase_collection.top.id
+  depends_on = [metabase_collection.grandchild]
+}
+
+data "metabase_collections" "grandchildren" {
+  parent_id  = metabase_collection.top.id
+  name_regex = "grandchild$"
+  depends_on = [metabase_collection.grandchild]
+}
+`, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.metabase_collections.subtree", "collections.#", "2"),
+					resource.TestCheckResourceAttrPair("data.metabase_collections.subtree", "collections.0.id", "metabase_collection.child", "id"),
+					resource.TestCheckResourceAttr("data.metabase_collections.grandchildren", "collections.#", "1"),
+					resource.TestCheckResourceAttrPair("data.metabase_collections.grandchildren", "collections.0.parent_id", "metabase_collection.child", "id"),
+				),
+			},
+		},
+	})
+}
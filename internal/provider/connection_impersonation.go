@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+)
+
+func NewConnectionImpersonation() resource.Resource {
+	connectionImpersonation := &ConnectionImpersonation{}
+
+	baseResource := &BaseResource{
+		TypeName: "connection_impersonation",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			connectionImpersonation.repository = repositories.NewConnectionImpersonationRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Enterprise. Connection impersonation of one permission group on one database: the group's queries run as the database role named by a user attribute, so the database's own access rules apply. The database's view-data for the group is set to \"impersonated\" in the same permissions graph update; on destroy it is set back to its previous level (see `previous_view_data`). Planning fails while the group's view-data on the database is set per table, e.g. with sandboxes, which impersonation would remove. Requires Metabase 50 or later with the `advanced_permissions` feature, and an engine with roles (e.g. PostgreSQL, Snowflake, Redshift); planning fails on instances without the feature.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Composite id \"<group_id>:<database_id>\"",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"group_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the permission group",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"database_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the database",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"attribute": schema.StringAttribute{
+						MarkdownDescription: "User attribute holding the database role each user's queries run as",
+						Required:            true,
+					},
+					"previous_view_data": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "View-data level of the database for the group before the policy was created, restored on destroy. \"blocked\" when it can't be restored as is: for imported policies, or when the level was granular per table (e.g. sandboxed).",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.ConnectionImpersonationTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			previous, err := connectionImpersonation.repository.ViewData(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to get the database's view-data: %s", err))
+				return
+			}
+			if repositories.IsPolicyViewData(previous) {
+				previous = "blocked"
+			}
+			plan.PreviousViewData = stringValue(previous)
+
+			err = connectionImpersonation.repository.Set(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString(), plan.Attribute.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to create connection impersonation: %s", err))
+				return
+			}
+
+			plan.Id = idOf(plan.GroupId.ValueString(), plan.DatabaseId.ValueString())
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.ConnectionImpersonationTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			groupId, databaseId, err := splitEdgeID(state.Id.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Read Error", err.Error())
+				return
+			}
+
+			impersonation, err := connectionImpersonation.repository.Find(ctx, groupId, databaseId)
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get connection impersonation: %s", err))
+				return
+			}
+			// Also gone when the database's view-data was changed from "impersonated".
+			if impersonation == nil {
+				resp.State.RemoveResource(ctx)
+				return
+			}
+
+			result := terraform.CreateConnectionImpersonationTerraformModelFromDTO(impersonation, state)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.ConnectionImpersonationTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			err := connectionImpersonation.repository.Set(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString(), plan.Attribute.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update connection impersonation: %s", err))
+				return
+			}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			var state terraform.ConnectionImpersonationTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			previous := state.PreviousViewData.ValueString()
+			if previous == "" {
+				previous = "blocked"
+			}
+			err := connectionImpersonation.repository.Delete(ctx, state.GroupId.ValueString(), state.DatabaseId.ValueString(), previous)
+			if err != nil {
+				resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("Unable to delete connection impersonation: %s", err))
+				return
+			}
+		},
+	}
+
+	connectionImpersonation.BaseResource = baseResource
+
+	return connectionImpersonation
+}
+
+var _ resource.ResourceWithModifyPlan = &ConnectionImpersonation{}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. It fails the plan on
+// instances without connection impersonation (OSS, or a license without
+// advanced permissions), and when the group has table-level view-data on the
+// database.
+func (c *ConnectionImpersonation) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || c.repository == nil {
+		return // destroy, or the provider is not configured yet
+	}
+	if err := c.repository.Supported(); err != nil {
+		resp.Diagnostics.AddError("Unsupported Feature", fmt.Sprintf("%s. Connection impersonation is a Metabase Enterprise feature.", err))
+		return
+	}
+	if req.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	var plan terraform.ConnectionImpersonationTerraformModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	c.checkTableViewData(ctx, plan, &resp.Diagnostics)
+}
+
+// checkTableViewData reports a group whose view-data on the database is set
+// per table, e.g. with sandboxes: the database-wide "impersonated" written by
+// the impersonation would remove them.
+func (c *ConnectionImpersonation) checkTableViewData(ctx context.Context, plan terraform.ConnectionImpersonationTerraformModel, diags *diag.Diagnostics) {
+	if plan.GroupId.IsUnknown() || plan.DatabaseId.IsUnknown() {
+		return
+	}
+	perTable, err := c.repository.HasTableViewData(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString())
+	if err != nil {
+		diags.AddError("Validation Error", fmt.Sprintf("Unable to get the database's view-data: %s", err))
+		return
+	}
+	if perTable {
+		diags.AddAttributeError(path.Root("database_id"), "Table-Level View-Data Would Be Replaced", fmt.Sprintf("Group %s has view-data set per table on database %s (e.g. sandboxed tables); impersonating the database would replace it and remove its sandboxes. Remove the group's `metabase_sandbox` resources on this database first.", plan.GroupId.ValueString(), plan.DatabaseId.ValueString()))
+	}
+}
+
+// ConnectionImpersonation defines the resource implementation.
+type ConnectionImpersonation struct {
+	*BaseResource
+	repository *repositories.ConnectionImpersonationRepository
+}
@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testAccCheckConnectionImpersonationDestroyed(s *terraform.State) error {
+	repo := repositories.NewConnectionImpersonationRepository(newTestMetabaseClient())
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metabase_connection_impersonation" {
+			continue
+		}
+		impersonation, err := repo.Find(context.Background(), rs.Primary.Attributes["group_id"], rs.Primary.Attributes["database_id"])
+		if err != nil {
+			return err
+		}
+		if impersonation != nil {
+			return fmt.Errorf("connection impersonation %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+// testAccCheckViewDataImpersonated asserts that the group's view-data was set
+// to "impersonated" along with the policy.
+func testAccCheckViewDataImpersonated(s *terraform.State) error {
+	rs := s.RootModule().Resources["metabase_connection_impersonation.test"]
+	groupId, databaseId := rs.Primary.Attributes["group_id"], rs.Primary.Attributes["database_id"]
+	permission, found, err := repositories.NewDatabasePermissionRepository(newTestMetabaseClient()).Get(context.Background(), groupId, databaseId)
+	if err != nil {
+		return err
+	}
+	if !found || permission.ViewData == nil || *permission.ViewData != "impersonated" {
+		return fmt.Errorf("expected view-data of group %s on database %s to be impersonated, got %+v", groupId, databaseId, permission)
+	}
+	return nil
+}
+
+func TestAccConnectionImpersonationResource(t *testing.T) {
+	name := fmt.Sprintf("Test impersonated group %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckTokenFeature(t, "advanced_permissions")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckConnectionImpersonationDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConnectionImpersonationConfig(name, "db_role"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_connection_impersonation.test", "attribute", "db_role"),
+					resource.TestCheckResourceAttrPair("metabase_connection_impersonation.test", "database_id", "metabase_database.a", "id"),
+					testAccCheckViewDataImpersonated,
+				),
+			},
+			{
+				ResourceName:      "metabase_connection_impersonation.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Not readable from Metabase: "blocked" on import.
+				ImportStateVerifyIgnore: []string{"previous_view_data"},
+			},
+			// The attribute changes in place.
+			{
+				Config: testAccConnectionImpersonationConfig(name, "warehouse_role"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_connection_impersonation.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_connection_impersonation.test", "attribute", "warehouse_role"),
+					testAccCheckViewDataImpersonated,
+				),
+			},
+		},
+	})
+}
+
+// TestAccConnectionImpersonationResource_RestoresViewData asserts that
+// removing the policy restores the database's previous view-data rather than
+// granting unrestricted access.
+func TestAccConnectionImpersonationResource_RestoresViewData(t *testing.T) {
+	name := fmt.Sprintf("Test blocked impersonated group %d", rand.Int())
+	var groupId, databaseId string
+
+	checkViewData := func(want string) func(*terraform.State) error {
+		return func(*terraform.State) error {
+			permission, found, err := repositories.NewDatabasePermissionRepository(newTestMetabaseClient()).Get(context.Background(), groupId, databaseId)
+			if err != nil {
+				return err
+			}
+			if !found || permission.ViewData == nil || *permission.ViewData != want {
+				return fmt.Errorf("expected view-data of group %s on database %s to be %s, got %+v", groupId, databaseId, want, permission)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckTokenFeature(t, "advanced_permissions")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckConnectionImpersonationDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConnectionImpersonationConfig(name, ""),
+				Check: func(s *terraform.State) error {
+					groupId = s.RootModule().Resources["metabase_permission_group.test"].Primary.ID
+					databaseId = s.RootModule().Resources["metabase_database.a"].Primary.ID
+					return nil
+				},
+			},
+			// Block the group on the database out-of-band, then impersonate.
+			{
+				PreConfig: func() {
+					blocked := dtos.DatabasePermissionDTO{CreateQueries: "no", ViewData: stringPtr("blocked")}
+					if err := repositories.NewDatabasePermissionRepository(newTestMetabaseClient()).Set(context.Background(), groupId, databaseId, blocked); err != nil {
+						t.Fatalf("unable to block the group: %s", err)
+					}
+				},
+				Config: testAccConnectionImpersonationConfig(name, "db_role"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_connection_impersonation.test", "previous_view_data", "blocked"),
+					testAccCheckViewDataImpersonated,
+				),
+			},
+			// Remove the policy only: the group is blocked again, not unrestricted.
+			{
+				Config: testAccConnectionImpersonationConfig(name, ""),
+				Check:  checkViewData("blocked"),
+			},
+		},
+	})
+}
+
+// TestAccConnectionImpersonationResource_SandboxedDatabase checks that
+// impersonating a database on which the group has a sandbox fails the plan
+// instead of removing the sandbox.
+func TestAccConnectionImpersonationResource_SandboxedDatabase(t *testing.T) {
+	name := fmt.Sprintf("Test sandboxed impersonated group %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSampleDatabase(t)
+			testAccPreCheckTokenFeature(t, "sandboxes")
+			testAccPreCheckTokenFeature(t, "advanced_permissions")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSandboxDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSandboxRestoreConfig(name, true),
+			},
+			{
+				Config: testAccSandboxRestoreConfig(name, true) + `
+resource "metabase_connection_impersonation" "test" {
+  group_id    = metabase_permission_group.test.id
+  database_id = data.metabase_database.sample.id
+  attribute   = "db_role"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Table-Level View-Data"),
+			},
+		},
+	})
+}
+
+// testAccConnectionImpersonationConfig declares no policy when attribute is "".
+func testAccConnectionImpersonationConfig(groupName, attribute string) string {
+	config := testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "a" {
+  name                = "%[1]s db"
+  engine              = "postgres"
+  deletion_protection = false
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+
+resource "metabase_permission_group" "test" {
+  name = %[1]q
+}
+`, groupName)
+	if attribute == "" {
+		return config
+	}
+	return config + fmt.Sprintf(`
+resource "metabase_connection_impersonation" "test" {
+  group_id    = metabase_permission_group.test.id
+  database_id = metabase_database.a.id
+  attribute   = %q
+}
+`, attribute)
+}
@@ -0,0 +1,300 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func NewDashboard() resource.Resource {
+	dashboard := &Dashboard{}
+
+	baseResource := &BaseResource{
+		TypeName: "dashboard",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			dashboard.repository = repositories.NewDashboardRepository(client)
+			dashboard.collectionRepository = repositories.NewCollectionRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "A dashboard: its parameters (filters), tabs and the cards laid out on it. Every change is applied in a single update.\n\n" +
+					"Tabs are identified by name and dashcards by the card they show and the tab they are on, not by their position in the list: re-ordering the list or moving and resizing cards updates the existing dashcards in place instead of re-creating them. A card shown twice on the same tab is told apart by its layout. Renaming a tab re-creates it, together with its dashcards.\n\n" +
+					"Removing the resource sends the dashboard to the Trash (never a permanent delete).",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Dashboard ID",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the dashboard",
+						Required:            true,
+					},
+					"description": schema.StringAttribute{
+						MarkdownDescription: "Description of the dashboard",
+						Optional:            true,
+					},
+					"collection_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the collection holding the dashboard. Omit for the root collection (\"Our Analytics\"). The collection must exist and not be in the Trash.",
+						Optional:            true,
+					},
+					"parameters": schema.StringAttribute{
+						MarkdownDescription: "The dashboard's parameters (filters) as a JSON array (use jsonencode), in the shape of Metabase's `parameters`, e.g. `[{ id = \"a1b2c3d4\", name = \"Category\", slug = \"category\", type = \"string/=\" }]`. Keys Metabase adds are ignored.",
+						CustomType:          terraform.JSONType{},
+						Optional:            true,
+						Validators:          []validator.String{JSONValidator()},
+					},
+					"tabs": schema.ListNestedAttribute{
+						MarkdownDescription: "Tabs of the dashboard, in display order. When set, every dashcard must name its `tab`.",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"name": schema.StringAttribute{
+									MarkdownDescription: "Name of the tab (unique within the dashboard)",
+									Required:            true,
+								},
+							},
+						},
+					},
+					"dashcards": schema.ListNestedAttribute{
+						MarkdownDescription: "Cards placed on the dashboard. The grid is 24 columns wide.",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"card_id": schema.StringAttribute{
+									MarkdownDescription: "ID of the card to show",
+									Required:            true,
+								},
+								"tab": schema.StringAttribute{
+									MarkdownDescription: "Name of the tab the card is on. Required when the dashboard has tabs.",
+									Optional:            true,
+								},
+								"row": schema.Int64Attribute{
+									MarkdownDescription: "Row of the top-left corner (0-based)",
+									Required:            true,
+								},
+								"col": schema.Int64Attribute{
+									MarkdownDescription: "Column of the top-left corner (0 to 23)",
+									Required:            true,
+								},
+								"size_x": schema.Int64Attribute{
+									MarkdownDescription: "Width in grid columns",
+									Required:            true,
+								},
+								"size_y": schema.Int64Attribute{
+									MarkdownDescription: "Height in grid rows",
+									Required:            true,
+								},
+								"parameter_mappings": schema.StringAttribute{
+									MarkdownDescription: "Wiring of dashboard parameters to the card as a JSON array (use jsonencode), e.g. `[{ parameter_id = \"a1b2c3d4\", card_id = 12, target = [\"dimension\", [\"field\", 34, null]] }]`.",
+									CustomType:          terraform.JSONType{},
+									Optional:            true,
+									Validators:          []validator.String{JSONValidator()},
+								},
+								"visualization_settings": schema.StringAttribute{
+									MarkdownDescription: "Visualization overrides for this dashcard as a JSON object (use jsonencode), e.g. `{ \"card.title\" = \"Revenue\" }`.",
+									CustomType:          terraform.JSONType{},
+									Optional:            true,
+									Validators:          []validator.String{JSONValidator()},
+								},
+							},
+						},
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.DashboardTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			input := dashboard.fromModel(ctx, plan, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			createResponse, err := dashboard.repository.Create(ctx, input)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to create dashboard: %s", err))
+				return
+			}
+			id := strconv.Itoa(createResponse.Id)
+
+			if len(plan.Tabs) > 0 || len(plan.Dashcards) > 0 {
+				err := dashboard.updateLayout(ctx, id, plan, input, createResponse)
+				if err != nil {
+					// Keep the created dashboard in state (tainted) so it is not orphaned.
+					result, convErr := terraform.CreateDashboardTerraformModelFromDTO(createResponse, plan)
+					if convErr == nil {
+						resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+					}
+					resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to lay out dashboard %s: %s", id, err))
+					return
+				}
+			}
+
+			getResponse, err := dashboard.repository.Get(ctx, id)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to read created dashboard: %s", err))
+				return
+			}
+			result, err := terraform.CreateDashboardTerraformModelFromDTO(getResponse, plan)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to read created dashboard: %s", err))
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.DashboardTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			getResponse, err := dashboard.repository.Get(ctx, state.Id.ValueString())
+			if err != nil {
+				// Deleted out-of-band (404): drop from state so it's recreated.
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.State.RemoveResource(ctx)
+					return
+				}
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get dashboard: %s", err))
+				return
+			}
+
+			result, err := terraform.CreateDashboardTerraformModelFromDTO(getResponse, state)
+			if err != nil {
+				resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to reconcile dashboard: %s", err))
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.DashboardTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			input := dashboard.fromModel(ctx, plan, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// Read the current layout to reuse the ids of unchanged tabs and dashcards.
+			current, err := dashboard.repository.Get(ctx, plan.Id.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to get dashboard: %s", err))
+				return
+			}
+
+			err = dashboard.updateLayout(ctx, plan.Id.ValueString(), plan, input, current)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update dashboard: %s", err))
+				return
+			}
+
+			// The new state is not read from the API
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			var state terraform.DashboardTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// Destroy = archive to Trash (recoverable), never a permanent delete.
+			err := dashboard.repository.Archive(ctx, state.Id.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Archive Error", fmt.Sprintf("Unable to archive dashboard: %s", err))
+				return
+			}
+		},
+	}
+
+	dashboard.BaseResource = baseResource
+
+	return dashboard
+}
+
+// fromModel converts the plan's dashboard fields (not the layout) into the API
+// shape, checking that the target collection exists and is not in the Trash.
+func (d *Dashboard) fromModel(ctx context.Context, plan terraform.DashboardTerraformModel, diags *diag.Diagnostics) *dtos.DashboardDTO {
+	input := &dtos.DashboardDTO{
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueStringPointer(),
+	}
+	if !plan.Parameters.IsNull() && !plan.Parameters.IsUnknown() {
+		input.Parameters = json.RawMessage(plan.Parameters.ValueString())
+	}
+
+	if plan.CollectionId.IsNull() {
+		return input
+	}
+	collectionId, err := strconv.Atoi(plan.CollectionId.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("collection_id"), "Invalid collection_id", fmt.Sprintf("%q is not a numeric collection ID.", plan.CollectionId.ValueString()))
+		return nil
+	}
+	collection, err := d.collectionRepository.Get(ctx, plan.CollectionId.ValueString())
+	if err != nil {
+		var notFound *metabase.NotFoundError
+		if errors.As(err, &notFound) {
+			diags.AddAttributeError(path.Root("collection_id"), "Invalid collection_id", fmt.Sprintf("Collection %s does not exist.", plan.CollectionId.ValueString()))
+			return nil
+		}
+		diags.AddError("Get Error", fmt.Sprintf("Unable to get collection %s: %s", plan.CollectionId.ValueString(), err))
+		return nil
+	}
+	if collection.Archived {
+		diags.AddAttributeError(path.Root("collection_id"), "Invalid collection_id", fmt.Sprintf("Collection %s is in the Trash; restore it or pick another collection.", plan.CollectionId.ValueString()))
+		return nil
+	}
+	input.CollectionId = &collectionId
+	return input
+}
+
+// updateLayout PUTs input together with the planned tabs and dashcards,
+// matched against current.
+func (d *Dashboard) updateLayout(ctx context.Context, id string, plan terraform.DashboardTerraformModel, input *dtos.DashboardDTO, current *dtos.DashboardDTO) error {
+	tabs, dashcards, err := terraform.BuildDashboardLayout(plan, current)
+	if err != nil {
+		return err
+	}
+	input.Tabs = tabs
+	input.Dashcards = dashcards
+	_, err = d.repository.Update(ctx, id, input)
+	return err
+}
+
+// Dashboard defines the resource implementation.
+type Dashboard struct {
+	*BaseResource
+	repository           *repositories.DashboardRepository
+	collectionRepository *repositories.CollectionRepository
+}
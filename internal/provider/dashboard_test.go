@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccCheckDashboardArchived asserts that destroy sends dashboards to the Trash.
+func testAccCheckDashboardArchived(s *terraform.State) error {
+	repo := repositories.NewDashboardRepository(newTestMetabaseClient())
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metabase_dashboard" {
+			continue
+		}
+		d, err := repo.Get(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("dashboard %s get failed after destroy: %w", rs.Primary.ID, err)
+		}
+		if !d.Archived {
+			return fmt.Errorf("dashboard %s is not archived after destroy", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+// testAccCheckDashcardIdUnchanged records the id of the dashcard at index in
+// state and, on later steps, checks it is still the same (re-layouts update in
+// place).
+func testAccCheckDashcardIdUnchanged(index int, id *int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["metabase_dashboard.test"]
+		if !ok {
+			return fmt.Errorf("metabase_dashboard.test not found in state")
+		}
+		d, err := repositories.NewDashboardRepository(newTestMetabaseClient()).Get(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		cardId := rs.Primary.Attributes[fmt.Sprintf("dashcards.%d.card_id", index)]
+		for _, dashcard := range d.Dashcards {
+			if dashcard.CardId == nil || strconv.Itoa(*dashcard.CardId) != cardId {
+				continue
+			}
+			if *id == 0 {
+				*id = dashcard.Id
+				return nil
+			}
+			if dashcard.Id != *id {
+				return fmt.Errorf("dashcard was re-created: id %d, expected %d", dashcard.Id, *id)
+			}
+			return nil
+		}
+		return fmt.Errorf("dashcard for card %s not found", cardId)
+	}
+}
+
+// TestAccDashboardResource covers create, import, and that moving and
+// re-ordering dashcards updates them in place.
+func TestAccDashboardResource(t *testing.T) {
+	name := fmt.Sprintf("Test dashboard %d", rand.Int())
+	var dashcardId int
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDashboardArchived,
+		Steps: []resource.TestStep{
+			// Create and Read.
+			{
+				Config: testAccDashboardResourceConfig(name, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_dashboard.test", "name", name),
+					resource.TestCheckResourceAttrPair("metabase_dashboard.test", "collection_id", "metabase_collection.test", "id"),
+					resource.TestCheckResourceAttr("metabase_dashboard.test", "tabs.#", "2"),
+					resource.TestCheckResourceAttr("metabase_dashboard.test", "dashcards.#", "2"),
+					resource.TestCheckResourceAttrPair("metabase_dashboard.test", "dashcards.0.card_id", "metabase_card.first", "id"),
+					resource.TestCheckResourceAttrSet("metabase_dashboard.test", "id"),
+					testAccCheckDashcardIdUnchanged(0, &dashcardId),
+				),
+			},
+			// ImportState. Parameters are imported in Metabase's normalized form.
+			{
+				ResourceName:            "metabase_dashboard.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"parameters"},
+			},
+			// Swap the dashcards in the list and move one: updated in place.
+			{
+				Config: testAccDashboardResourceConfig(name, true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_dashboard.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("metabase_dashboard.test", "dashcards.1.card_id", "metabase_card.first", "id"),
+					resource.TestCheckResourceAttr("metabase_dashboard.test", "dashcards.1.row", "6"),
+					testAccCheckDashcardIdUnchanged(1, &dashcardId),
+				),
+			},
+		},
+	})
+}
+
+func testAccDashboardResourceConfig(name string, relayout bool) string {
+	first := `{
+      card_id = metabase_card.first.id
+      tab     = "Overview"
+      row     = 0
+      col     = 0
+      size_x  = 6
+      size_y  = 4
+      parameter_mappings = jsonencode([{
+        parameter_id = "a1b2c3d4"
+        card_id      = tonumber(metabase_card.first.id)
+        target       = ["variable", ["template-tag", "n"]]
+      }])
+    }`
+	second := `{
+      card_id = metabase_card.second.id
+      tab     = "Details"
+      row     = 0
+      col     = 0
+      size_x  = 12
+      size_y  = 6
+      visualization_settings = jsonencode({ "card.title" = "Second" })
+    }`
+	dashcards := first + ",\n    " + second
+	if relayout {
+		dashcards = second + ",\n    " + replaceRow(first)
+	}
+
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                = "%[1]s db"
+  engine              = "postgres"
+  deletion_protection = false
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+
+resource "metabase_collection" "test" {
+  name = "%[1]s collection"
+}
+
+resource "metabase_card" "first" {
+  name          = "%[1]s first"
+  collection_id = metabase_collection.test.id
+  display       = "scalar"
+  dataset_query = jsonencode({
+    database = tonumber(metabase_database.test.id)
+    type     = "native"
+    native = {
+      query = "SELECT {{n}}"
+      template-tags = {
+        n = { id = "0f0f0f0f", name = "n", display-name = "N", type = "number" }
+      }
+    }
+  })
+}
+
+resource "metabase_card" "second" {
+  name          = "%[1]s second"
+  collection_id = metabase_collection.test.id
+  dataset_query = jsonencode({
+    database = tonumber(metabase_database.test.id)
+    type     = "native"
+    native   = { query = "SELECT 2" }
+  })
+}
+
+resource "metabase_dashboard" "test" {
+  name          = "%[1]s"
+  description   = "Managed by Terraform"
+  collection_id = metabase_collection.test.id
+  parameters = jsonencode([{
+    id   = "a1b2c3d4"
+    name = "N"
+    slug = "n"
+    type = "number/="
+  }])
+
+  tabs = [{ name = "Overview" }, { name = "Details" }]
+
+  dashcards = [
+    %[2]s
+  ]
+}
+`, name, dashcards)
+}
+
+func replaceRow(dashcard string) string {
+	return strings.Replace(dashcard, "row     = 0", "row     = 6", 1)
+}
@@ -5,16 +5,30 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -27,48 +41,150 @@ func NewDatabase() resource.Resource {
 			database.repository = repositories.NewDatabaseRepository(client)
 		},
 		GetSchema: func(ctx context.Context) schema.Schema {
-			return schema.Schema{
-				MarkdownDescription: "A database connection in Metabase.",
-				Attributes: map[string]schema.Attribute{
-					"id": schema.StringAttribute{
-						Computed:            true,
-						MarkdownDescription: "Database ID",
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.UseStateForUnknown(),
-						},
+			attributes := map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "Database ID",
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
 					},
-					"name": schema.StringAttribute{
-						MarkdownDescription: "Name of the database",
-						Required:            true,
+				},
+				"name": schema.StringAttribute{
+					MarkdownDescription: "Name of the database",
+					Required:            true,
+				},
+				"engine": schema.StringAttribute{
+					MarkdownDescription: "Database engine (e.g. postgres, mysql, h2, bigquery-cloud-sdk). Required with `details`; set from the typed connection block otherwise.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
+						stringplanmodifier.RequiresReplace(),
 					},
-					"engine": schema.StringAttribute{
-						MarkdownDescription: "Database engine (e.g. postgres, mysql, h2, bigquery-cloud-sdk)",
-						Required:            true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.RequiresReplace(),
+				},
+				// Config is authoritative; read back only when redacted_attributes is set.
+				"details": schema.StringAttribute{
+					MarkdownDescription: "Connection details as a JSON object (use jsonencode), for engines without a typed connection block. Config is authoritative. By default it is not read back from Metabase; set redacted_attributes to detect drift on non-secret fields. Put secrets in `details_wo` to keep them out of the state. Exactly one of `details` and the typed connection blocks (`postgres`, `mysql`, …) must be set.",
+					Optional:            true,
+					Sensitive:           true,
+				},
+				// Write-only: merged into the details sent to Metabase, never stored.
+				"details_wo": schema.StringAttribute{
+					MarkdownDescription: "Secret connection details as a JSON object (use jsonencode), e.g. `{ password = var.db_password }`, merged over `details` or the typed connection block. Write-only: never stored in the plan or state (requires Terraform 1.11 or later). Only sent on create and when the connection changes; bump `details_wo_version` to rotate them.",
+					Optional:            true,
+					Sensitive:           true,
+					WriteOnly:           true,
+					Validators:          []validator.String{JSONValidator()},
+				},
+				"details_wo_version": schema.Int64Attribute{
+					MarkdownDescription: "Version of the `details_wo` secrets. Changing it sends them to Metabase again, e.g. after a password rotation.",
+					Optional:            true,
+				},
+				// Enables drift detection on the non-secret details fields (see Read).
+				"redacted_attributes": schema.SetAttribute{
+					MarkdownDescription: "Keys inside details that Metabase returns redacted (e.g. \"password\", \"service-account-json\"). Setting this enables drift detection on the remaining, non-secret fields. Typed connection blocks know their redacted keys and always detect drift.",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+				// Terraform-only: wait for the initial sync so dependent resources
+				// (table metadata, granular permissions) find the tables.
+				"wait_for_sync": schema.BoolAttribute{
+					MarkdownDescription: "If true, create waits until Metabase's initial sync of the database is complete, so resources depending on its tables and fields (e.g. `metabase_table_metadata`, `create_queries_schemas`) don't race it. A sync that is aborted or outlasts `wait_for_sync_timeout` is reported as a warning. Defaults to false.",
+					Optional:            true,
+					Computed:            true,
+					Default:             booldefault.StaticBool(false),
+				},
+				"wait_for_sync_timeout": schema.StringAttribute{
+					MarkdownDescription: "How long `wait_for_sync` waits, e.g. \"30m\". Defaults to \"10m\".",
+					Optional:            true,
+					Computed:            true,
+					Default:             stringdefault.StaticString(defaultWaitForSyncTimeout),
+					Validators:          []validator.String{DurationValidator()},
+				},
+				"validate_connection": schema.BoolAttribute{
+					MarkdownDescription: "If true, plan tests the connection with the new details (on create and when engine or details change) and fails with the driver's error, so bad credentials are caught by `terraform plan`. Requires details and the provider configuration to be known at plan time. Defaults to false.",
+					Optional:            true,
+					Computed:            true,
+					Default:             booldefault.StaticBool(false),
+				},
+				"sync_on_change": schema.BoolAttribute{
+					MarkdownDescription: "If true, a change to details starts a metadata sync, so tables added or removed behind the new connection show up without waiting for the sync schedule. Defaults to false.",
+					Optional:            true,
+					Computed:            true,
+					Default:             booldefault.StaticBool(false),
+				},
+				"schedules": schema.SingleNestedAttribute{
+					MarkdownDescription: "When Metabase syncs the schema and scans field values, as Quartz cron strings. Metabase only keeps custom schedules when details sets `\"let-user-control-scheduling\" = true` (a typed connection block sets it); otherwise it picks its own. Defaults to Metabase's choice.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers:       []planmodifier.Object{objectplanmodifier.UseStateForUnknown()},
+					Attributes: map[string]schema.Attribute{
+						"metadata_sync": schema.StringAttribute{
+							MarkdownDescription: "Schedule of the metadata sync (tables and fields), e.g. `\"0 0 2 * * ? *\"` for daily at 02:00. Hourly, daily, weekly (`\"0 0 2 ? * MON *\"`) and monthly (`\"0 0 2 1 * ? *\"`, `\"0 0 2 ? * 2#1 *\"`) schedules are supported.",
+							Optional:            true,
+							Computed:            true,
+							PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+							Validators:          []validator.String{CronScheduleValidator()},
+						},
+						"cache_field_values": schema.StringAttribute{
+							MarkdownDescription: "Schedule of the field values scan (filter values), in the same form as `metadata_sync`. Null when `is_full_sync` is false: values are then not scanned on a schedule.",
+							Optional:            true,
+							Computed:            true,
+							PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+							Validators:          []validator.String{CronScheduleValidator()},
 						},
 					},
-					// Config is authoritative; read back only when redacted_attributes is set.
-					"details": schema.StringAttribute{
-						MarkdownDescription: "Connection details as a JSON object (use jsonencode). Config is authoritative. By default it is not read back from Metabase; set redacted_attributes to detect drift on non-secret fields.",
-						Required:            true,
-						Sensitive:           true,
-					},
-					// Enables drift detection on the non-secret details fields (see Read).
-					"redacted_attributes": schema.SetAttribute{
-						MarkdownDescription: "Keys inside details that Metabase returns redacted (e.g. \"password\", \"service-account-json\"). Setting this enables drift detection on the remaining, non-secret fields.",
-						ElementType:         types.StringType,
-						Optional:            true,
-					},
-					// Terraform-only guard: deleting a database hard-deletes all content built on it.
-					"deletion_protection": schema.BoolAttribute{
-						MarkdownDescription: "If true (default), refuses to delete the database. Metabase hard-deletes a database and all content built on it, so set this to false and apply before destroying.",
-						Optional:            true,
-						Computed:            true,
-						Default:             booldefault.StaticBool(true),
-					},
+				},
+				"is_full_sync": schema.BoolAttribute{
+					MarkdownDescription: "Whether field values are scanned on the `cache_field_values` schedule. Metabase defaults to true.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+				},
+				"is_on_demand": schema.BoolAttribute{
+					MarkdownDescription: "Whether field values are scanned only when a filter widget needs them (with `is_full_sync = false`). Metabase defaults to false.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+				},
+				"auto_run_queries": schema.BoolAttribute{
+					MarkdownDescription: "Whether query builder questions run automatically as they are edited. Metabase defaults to true.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+				},
+				"refingerprint": schema.BoolAttribute{
+					MarkdownDescription: "Whether the sync periodically re-fingerprints fields (samples their values to infer semantic types). Null leaves Metabase's global setting in effect.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+				},
+				"cache_ttl": schema.Int64Attribute{
+					MarkdownDescription: "Hours query results on this database are cached; null for the instance default. Requires the Enterprise `cache_granular_controls` feature.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+				},
+				"settings": schema.StringAttribute{
+					MarkdownDescription: "Database-local settings as a JSON object (use jsonencode), e.g. `{ \"database-enable-actions\" = true }`. Keys removed from the config are unset; keys set outside Terraform are ignored. Not managed when unset.",
+					CustomType:          terraform.JSONType{},
+					Optional:            true,
+					Validators:          []validator.String{JSONValidator()},
+				},
+				// Terraform-only guard: deleting a database hard-deletes all content built on it.
+				"deletion_protection": schema.BoolAttribute{
+					MarkdownDescription: "If true (default), refuses to delete the database. Metabase hard-deletes a database and all content built on it, so set this to false and apply before destroying.",
+					Optional:            true,
+					Computed:            true,
+					Default:             booldefault.StaticBool(true),
 				},
 			}
+			maps.Copy(attributes, databaseEngineAttributes())
+
+			return schema.Schema{
+				MarkdownDescription: "A database connection in Metabase.",
+				Attributes:          attributes,
+			}
 		},
 		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 			var plan terraform.DatabaseTerraformModel
@@ -77,14 +193,73 @@ func NewDatabase() resource.Resource {
 				return
 			}
 
-			createResponse, err := database.repository.Create(ctx, plan.Name.ValueString(), plan.Engine.ValueString(), plan.Details.ValueString())
+			options, err := databaseOptionsInput(plan, nil)
 			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to read database settings: %s", err))
+				return
+			}
+
+			var detailsWO types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("details_wo"), &detailsWO)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			secrets, err := databaseSecrets(detailsWO)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", err.Error())
+				return
+			}
+			var schedules types.Object
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schedules"), &schedules)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			customSchedules := schedulesConfigured(schedules)
+			engine, details, err := databaseConnection(&plan, secrets, customSchedules)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to encode connection details: %s", err))
+				return
+			}
+
+			createResponse, err := database.repository.Create(ctx, plan.Name.ValueString(), engine, details, options)
+			if err != nil {
+				if createResponse != nil {
+					// Keep the created database in state (tainted) so it is not orphaned.
+					result, convErr := terraform.CreateDatabaseTerraformModelFromDTO(createResponse, plan)
+					if convErr == nil {
+						resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+					}
+				}
 				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to create database: %s", err))
 				return
 			}
 
-			result := terraform.CreateDatabaseTerraformModelFromDTO(createResponse, plan.Details, plan.RedactedAttributes, plan.DeletionProtection)
+			result, err := terraform.CreateDatabaseTerraformModelFromDTO(createResponse, plan)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to read database: %s", err))
+				return
+			}
 			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+			if resp.Diagnostics.HasError() || !plan.WaitForSync.ValueBool() {
+				return
+			}
+
+			timeout, _ := time.ParseDuration(plan.WaitForSyncTimeout.ValueString()) // checked by DurationValidator
+			waitCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			// An unfinished sync is a warning: an error would taint the
+			// database, and replacing it deletes what was built on it.
+			_, err = database.repository.WaitForInitialSync(waitCtx, result.Id.ValueString(), databaseSyncPollInterval)
+			var aborted *repositories.SyncAbortedError
+			switch {
+			case err == nil:
+			case errors.Is(err, context.DeadlineExceeded):
+				resp.Diagnostics.AddWarning("Sync Incomplete", fmt.Sprintf("Database %s was created, but its initial sync is not complete after %s: resources depending on its tables may not find them yet. Raise wait_for_sync_timeout if the sync needs longer.", result.Id.ValueString(), plan.WaitForSyncTimeout.ValueString()))
+			case errors.As(err, &aborted):
+				resp.Diagnostics.AddWarning("Sync Aborted", fmt.Sprintf("Database %s was created, but %s.", result.Id.ValueString(), err))
+			default:
+				resp.Diagnostics.AddError("Sync Error", fmt.Sprintf("Database %s was created but its sync status could not be read: %s. Terraform marks it tainted; run `terraform untaint` once the sync is done.", result.Id.ValueString(), err))
+			}
 		},
 		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 			var state terraform.DatabaseTerraformModel
@@ -103,11 +278,28 @@ func NewDatabase() resource.Resource {
 				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get database: %s", err))
 				return
 			}
+			syncOptionDefaults(&state)
 
-			// details is reconciled with the API only when redacted_attributes is set;
+			// A typed connection block is always reconciled with the API, on the keys
+			// it sets. details is reconciled only when redacted_attributes is set;
 			// otherwise the config is kept verbatim (not read back).
-			details := state.Details
-			if !state.RedactedAttributes.IsNull() {
+			if e, block := typedEngine(&state); e != nil {
+				existing, err := json.Marshal(e.details(block))
+				if err != nil {
+					resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to encode connection details: %s", err))
+					return
+				}
+				reconciled, err := terraform.ReconcileDetails(getResponse.Details, string(existing), e.redacted())
+				var details map[string]any
+				if err == nil {
+					err = json.Unmarshal([]byte(reconciled), &details)
+				}
+				if err != nil {
+					resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to reconcile database details: %s", err))
+					return
+				}
+				*engineBlocks(&state)[e.attribute] = e.object(details, block)
+			} else if !state.RedactedAttributes.IsNull() {
 				var redacted []string
 				resp.Diagnostics.Append(state.RedactedAttributes.ElementsAs(ctx, &redacted, false)...)
 				if resp.Diagnostics.HasError() {
@@ -118,10 +310,14 @@ func NewDatabase() resource.Resource {
 					resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to reconcile database details: %s", err))
 					return
 				}
-				details = types.StringValue(reconciled)
+				state.Details = types.StringValue(reconciled)
 			}
 
-			result := terraform.CreateDatabaseTerraformModelFromDTO(getResponse, details, state.RedactedAttributes, state.DeletionProtection)
+			result, err := terraform.CreateDatabaseTerraformModelFromDTO(getResponse, state)
+			if err != nil {
+				resp.Diagnostics.AddError("Read Error", fmt.Sprintf("Unable to read database: %s", err))
+				return
+			}
 			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
 		},
 		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -133,20 +329,71 @@ func NewDatabase() resource.Resource {
 				return
 			}
 
-			// Send details only when they changed: writing details makes Metabase
-			// re-run the connection test and schema sync, so skip it on a name-only edit.
+			// Send details only when they changed (or details_wo_version did, to
+			// rotate the write-only secrets): writing details makes Metabase re-run
+			// the connection test and schema sync, so skip it on a name-only edit.
+			var schedules types.Object
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schedules"), &schedules)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			customSchedules := schedulesConfigured(schedules)
+			_, plannedDetails, err := databaseConnection(&plan, nil, customSchedules)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to encode connection details: %s", err))
+				return
+			}
+			_, currentDetails, err := databaseConnection(&state, nil, customSchedules)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to encode connection details: %s", err))
+				return
+			}
 			var details *string
-			if !plan.Details.Equal(state.Details) {
-				details = plan.Details.ValueStringPointer()
+			if plannedDetails != currentDetails || !plan.DetailsWOVersion.Equal(state.DetailsWOVersion) {
+				var detailsWO types.String
+				resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("details_wo"), &detailsWO)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				secrets, err := databaseSecrets(detailsWO)
+				if err != nil {
+					resp.Diagnostics.AddError("Update Error", err.Error())
+					return
+				}
+				// Secrets are sent with every details write: Metabase would
+				// otherwise drop the ones missing from the new details.
+				_, withSecrets, err := databaseConnection(&plan, secrets, customSchedules)
+				if err != nil {
+					resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to encode connection details: %s", err))
+					return
+				}
+				details = &withSecrets
+			}
+
+			options, err := databaseOptionsInput(plan, &state)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to read database settings: %s", err))
+				return
 			}
 
-			_, err := database.repository.Update(ctx, plan.Id.ValueString(), plan.Name.ValueStringPointer(), details)
+			updateResponse, err := database.repository.Update(ctx, plan.Id.ValueString(), plan.Name.ValueStringPointer(), details, options)
 			if err != nil {
 				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update database: %s", err))
 				return
 			}
 
-			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			result, err := terraform.CreateDatabaseTerraformModelFromDTO(updateResponse, plan)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to read database: %s", err))
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+
+			if details != nil && plan.SyncOnChange.ValueBool() {
+				if err := database.repository.SyncSchema(ctx, plan.Id.ValueString()); err != nil {
+					resp.Diagnostics.AddError("Sync Error", fmt.Sprintf("Database updated, but unable to start a sync: %s", err))
+				}
+			}
 		},
 		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 			var state terraform.DatabaseTerraformModel
@@ -176,6 +423,299 @@ func NewDatabase() resource.Resource {
 	return database
 }
 
+// defaultWaitForSyncTimeout is wait_for_sync_timeout when unset.
+const defaultWaitForSyncTimeout = "10m"
+
+// syncOptionDefaults fills the sync and validation options left null by
+// import (Terraform-only, so not read from Metabase) with their defaults, as
+// a configuration leaving them unset plans them.
+func syncOptionDefaults(m *terraform.DatabaseTerraformModel) {
+	if m.WaitForSync.IsNull() {
+		m.WaitForSync = types.BoolValue(false)
+	}
+	if m.WaitForSyncTimeout.IsNull() {
+		m.WaitForSyncTimeout = types.StringValue(defaultWaitForSyncTimeout)
+	}
+	if m.ValidateConnection.IsNull() {
+		m.ValidateConnection = types.BoolValue(false)
+	}
+	if m.SyncOnChange.IsNull() {
+		m.SyncOnChange = types.BoolValue(false)
+	}
+}
+
+// databaseSyncPollInterval is how often wait_for_sync checks the sync status.
+const databaseSyncPollInterval = 2 * time.Second
+
+// databaseOptionsInput returns the options to write: on create (state nil)
+// every known value, on update only the ones that changed. Settings keys
+// dropped from the config are sent as null to unset them.
+func databaseOptionsInput(plan terraform.DatabaseTerraformModel, state *terraform.DatabaseTerraformModel) (dtos.DatabaseOptionsDTO, error) {
+	prior := terraform.DatabaseTerraformModel{
+		Schedules:      types.ObjectNull(terraform.DatabaseSchedulesType.AttrTypes),
+		IsFullSync:     types.BoolNull(),
+		IsOnDemand:     types.BoolNull(),
+		AutoRunQueries: types.BoolNull(),
+		Refingerprint:  types.BoolNull(),
+		CacheTTL:       types.Int64Null(),
+		Settings:       terraform.NewJSONNull(),
+	}
+	if state != nil {
+		prior = *state
+	}
+	changedString := func(planned, current types.String) *string {
+		if planned.Equal(current) {
+			return nil
+		}
+		return knownStringPointer(planned)
+	}
+	changedBool := func(planned, current types.Bool) *bool {
+		if planned.Equal(current) {
+			return nil
+		}
+		return knownBoolPointer(planned)
+	}
+
+	options := dtos.DatabaseOptionsDTO{
+		MetadataSyncSchedule:     changedString(scheduleOf(plan.Schedules, "metadata_sync"), scheduleOf(prior.Schedules, "metadata_sync")),
+		CacheFieldValuesSchedule: changedString(scheduleOf(plan.Schedules, "cache_field_values"), scheduleOf(prior.Schedules, "cache_field_values")),
+		IsFullSync:               changedBool(plan.IsFullSync, prior.IsFullSync),
+		IsOnDemand:               changedBool(plan.IsOnDemand, prior.IsOnDemand),
+		AutoRunQueries:           changedBool(plan.AutoRunQueries, prior.AutoRunQueries),
+		Refingerprint:            changedBool(plan.Refingerprint, prior.Refingerprint),
+	}
+	if !plan.CacheTTL.IsNull() && !plan.CacheTTL.IsUnknown() && !plan.CacheTTL.Equal(prior.CacheTTL) {
+		cacheTTL := int(plan.CacheTTL.ValueInt64())
+		options.CacheTTL = &cacheTTL
+	}
+
+	if plan.Settings.IsNull() || plan.Settings.IsUnknown() || plan.Settings.Equal(prior.Settings) {
+		return options, nil
+	}
+	var settings, priorSettings map[string]any
+	if err := json.Unmarshal([]byte(plan.Settings.ValueString()), &settings); err != nil {
+		return options, fmt.Errorf("invalid settings JSON: %w", err)
+	}
+	if !prior.Settings.IsNull() && !prior.Settings.IsUnknown() {
+		if err := json.Unmarshal([]byte(prior.Settings.ValueString()), &priorSettings); err != nil {
+			return options, fmt.Errorf("invalid settings JSON in state: %w", err)
+		}
+	}
+	for k := range priorSettings {
+		if _, ok := settings[k]; !ok {
+			settings[k] = nil
+		}
+	}
+	options.Settings = settings
+	return options, nil
+}
+
+// scheduleOf returns one cron string of a schedules object; unknown when the
+// whole object is.
+func scheduleOf(schedules types.Object, key string) types.String {
+	if schedules.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if schedules.IsNull() {
+		return types.StringNull()
+	}
+	if v, ok := schedules.Attributes()[key].(types.String); ok {
+		return v
+	}
+	return types.StringNull()
+}
+
+// schedulesConfigured reports whether the config sets a sync or scan
+// schedule. Not the plan or state: schedules is computed, so once read it is
+// always known there.
+func schedulesConfigured(schedules types.Object) bool {
+	return !scheduleOf(schedules, "metadata_sync").IsNull() || !scheduleOf(schedules, "cache_field_values").IsNull()
+}
+
+var _ resource.ResourceWithValidateConfig = &Database{}
+
+// ValidateConfig implements resource.ResourceWithValidateConfig.
+func (d *Database) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config terraform.DatabaseTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Exactly one of details and the typed connection blocks.
+	var connections []string
+	if !config.Details.IsNull() {
+		connections = append(connections, "details")
+	}
+	blocks := engineBlocks(&config)
+	for _, e := range databaseEngines {
+		if !blocks[e.attribute].IsNull() {
+			connections = append(connections, e.attribute)
+		}
+	}
+	switch {
+	case len(connections) == 0:
+		resp.Diagnostics.AddError("Missing Connection Details", "Set `details` or one typed connection block: `postgres`, `mysql`, `redshift`, `snowflake`, `bigquery_cloud_sdk` or `h2`.")
+	case len(connections) > 1:
+		resp.Diagnostics.AddError("Invalid Attribute Combination", fmt.Sprintf("Only one of `details` and the typed connection blocks can be set, got %s.", strings.Join(connections, ", ")))
+	case connections[0] == "details":
+		if config.Engine.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("engine"), "Missing Attribute", "`engine` is required with `details`.")
+		}
+	default:
+		e, _ := typedEngine(&config)
+		if !config.Engine.IsNull() && !config.Engine.IsUnknown() && config.Engine.ValueString() != e.engine {
+			resp.Diagnostics.AddAttributeError(path.Root("engine"), "Invalid Attribute Combination", fmt.Sprintf("`%s` connects a %q database, but engine is %q: remove `engine`.", e.attribute, e.engine, config.Engine.ValueString()))
+		}
+		if !config.RedactedAttributes.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("redacted_attributes"), "Invalid Attribute Combination", fmt.Sprintf("`redacted_attributes` only applies to `details`: the redacted keys of `%s` are known.", e.attribute))
+		}
+	}
+
+	// A secret in both details_wo and the stored details would land in state.
+	if secrets, err := databaseSecrets(config.DetailsWO); err == nil && len(secrets) > 0 {
+		var stored map[string]any
+		if e, block := typedEngine(&config); e != nil {
+			stored = e.details(block)
+		} else if !config.Details.IsNull() && !config.Details.IsUnknown() {
+			_ = json.Unmarshal([]byte(config.Details.ValueString()), &stored)
+		}
+		var both []string
+		for k := range secrets {
+			if _, ok := stored[k]; ok {
+				both = append(both, k)
+			}
+		}
+		if len(both) > 0 {
+			slices.Sort(both)
+			resp.Diagnostics.AddAttributeError(path.Root("details_wo"), "Invalid Attribute Combination", fmt.Sprintf("%s set in both details_wo and the stored connection details: remove them from the latter so they stay out of the state.", strings.Join(both, ", ")))
+		}
+	}
+
+	metadataSync := scheduleOf(config.Schedules, "metadata_sync")
+	cacheFieldValues := scheduleOf(config.Schedules, "cache_field_values")
+
+	if config.IsFullSync.Equal(types.BoolValue(false)) && !cacheFieldValues.IsNull() && !cacheFieldValues.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("schedules").AtName("cache_field_values"), "Invalid Attribute Combination", "Field values are only scanned on a schedule with `is_full_sync = true`: remove `cache_field_values` or enable `is_full_sync`.")
+	}
+
+	// Without this flag Metabase replaces custom schedules with its own.
+	customSchedules := (!metadataSync.IsNull() && !metadataSync.IsUnknown()) || (!cacheFieldValues.IsNull() && !cacheFieldValues.IsUnknown())
+	if customSchedules && !config.Details.IsNull() && !config.Details.IsUnknown() {
+		var details map[string]any
+		if err := json.Unmarshal([]byte(config.Details.ValueString()), &details); err == nil && details["let-user-control-scheduling"] != true {
+			resp.Diagnostics.AddAttributeError(path.Root("schedules"), "Missing Scheduling Option", "Metabase only keeps custom sync and scan schedules when details sets `\"let-user-control-scheduling\" = true`. Add it to details or remove `schedules`.")
+		}
+	}
+}
+
+var _ resource.ResourceWithModifyPlan = &Database{}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. It sets engine from
+// a typed connection block, tests the connection when validate_connection is
+// set, and plans the field values schedule Metabase drops when is_full_sync
+// is turned off (and picks when it is turned back on) instead of carrying the
+// old schedule over.
+func (d *Database) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return // destroy
+	}
+	var config, plan terraform.DatabaseTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state *terraform.DatabaseTerraformModel
+	if !req.State.Raw.IsNull() {
+		state = &terraform.DatabaseTerraformModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	modified := false
+	if e, _ := typedEngine(&plan); e != nil && !plan.Engine.Equal(types.StringValue(e.engine)) {
+		plan.Engine = types.StringValue(e.engine)
+		modified = true
+		// RequiresReplace ran on the engine carried over from state.
+		if state != nil && !state.Engine.Equal(plan.Engine) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("engine"))
+		}
+	}
+
+	d.validateConnection(ctx, config, plan, state, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// On create, the schedules are unknown until Metabase picks them.
+	if state != nil && scheduleOf(config.Schedules, "cache_field_values").IsNull() && !plan.IsFullSync.IsUnknown() && !plan.IsFullSync.Equal(state.IsFullSync) && !plan.Schedules.IsUnknown() && !plan.Schedules.IsNull() {
+		cacheFieldValues := types.StringUnknown()
+		if !plan.IsFullSync.ValueBool() {
+			cacheFieldValues = types.StringNull()
+		}
+		plan.Schedules = types.ObjectValueMust(terraform.DatabaseSchedulesType.AttrTypes, map[string]attr.Value{
+			"metadata_sync":      scheduleOf(plan.Schedules, "metadata_sync"),
+			"cache_field_values": cacheFieldValues,
+		})
+		modified = true
+	}
+
+	if modified {
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	}
+}
+
+// validateConnection runs Metabase's connection test on the planned details
+// (with the write-only secrets from config) when validate_connection is set
+// and they are new (create) or changed.
+func (d *Database) validateConnection(ctx context.Context, config terraform.DatabaseTerraformModel, plan terraform.DatabaseTerraformModel, state *terraform.DatabaseTerraformModel, resp *resource.ModifyPlanResponse) {
+	if d.repository == nil || !plan.ValidateConnection.ValueBool() || plan.Engine.IsUnknown() || plan.Details.IsUnknown() || config.DetailsWO.IsUnknown() {
+		return
+	}
+	e, block := typedEngine(&plan)
+	if e != nil && hasUnknown(block) {
+		return
+	}
+	customSchedules := schedulesConfigured(config.Schedules)
+	_, planned, err := databaseConnection(&plan, nil, customSchedules)
+	if err != nil {
+		resp.Diagnostics.AddError("Validation Error", fmt.Sprintf("Unable to encode connection details: %s", err))
+		return
+	}
+	if state != nil {
+		_, current, err := databaseConnection(state, nil, customSchedules)
+		if err == nil && plan.Engine.Equal(state.Engine) && planned == current && plan.DetailsWOVersion.Equal(state.DetailsWOVersion) {
+			return
+		}
+	}
+	secrets, err := databaseSecrets(config.DetailsWO)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("details_wo"), "Validation Error", err.Error())
+		return
+	}
+	engine, details, err := databaseConnection(&plan, secrets, customSchedules)
+	if err != nil {
+		resp.Diagnostics.AddError("Validation Error", fmt.Sprintf("Unable to encode connection details: %s", err))
+		return
+	}
+
+	attribute := path.Root("details")
+	if e != nil {
+		attribute = path.Root(e.attribute)
+	}
+	result, err := d.repository.ValidateConnection(ctx, engine, details)
+	if err != nil {
+		resp.Diagnostics.AddError("Validation Error", fmt.Sprintf("Unable to test the database connection: %s", err))
+		return
+	}
+	if !result.Valid {
+		resp.Diagnostics.AddAttributeError(attribute, "Invalid Connection", fmt.Sprintf("Metabase could not connect to the %s database with these details: %s", engine, result.Message))
+	}
+}
+
 // Database defines the resource implementation.
 type Database struct {
 	*BaseResource
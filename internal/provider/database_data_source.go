@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+func NewDatabaseDataSource() datasource.DataSource {
+	database := &DatabaseDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "database",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			database.repository = repositories.NewDatabaseRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Looks up an existing database connection by id or by name, e.g. one managed by another team. Connection details are not exposed.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						MarkdownDescription: "Database ID. Set exactly one of id or name.",
+						Optional:            true,
+						Computed:            true,
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Exact name of the database. Set exactly one of id or name.",
+						Optional:            true,
+						Computed:            true,
+					},
+					"engine": schema.StringAttribute{
+						MarkdownDescription: "Engine of the database, e.g. \"postgres\"",
+						Computed:            true,
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.DatabaseDataSourceTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if config.Id.IsNull() == config.Name.IsNull() {
+				resp.Diagnostics.AddError("Invalid lookup", "Set exactly one of id or name.")
+				return
+			}
+
+			var found *dtos.DatabaseDTO
+			var err error
+			if !config.Id.IsNull() {
+				found, err = database.repository.Get(ctx, config.Id.ValueString())
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No database with id %s", config.Id.ValueString()))
+					return
+				}
+			} else {
+				found, err = database.repository.FindByName(ctx, config.Name.ValueString())
+				if err == nil && found == nil {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No database named %q", config.Name.ValueString()))
+					return
+				}
+			}
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get database: %s", err))
+				return
+			}
+
+			result := terraform.CreateDatabaseDataSourceTerraformModelFromDTO(found)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+	}
+
+	database.BaseDataSource = baseDataSource
+
+	return database
+}
+
+// DatabaseDataSource defines the data source implementation.
+type DatabaseDataSource struct {
+	*BaseDataSource
+	repository *repositories.DatabaseRepository
+}
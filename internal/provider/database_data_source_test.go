@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccDatabaseDataSource looks up a database by name and by id.
+func TestAccDatabaseDataSource(t *testing.T) {
+	name := fmt.Sprintf("Test database ds %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseResourceConfig(name) + `
+data "metabase_database" "by_name" {
+  name = metabase_database.test.name
+}
+
+data "metabase_database" "by_id" {
+  id = metabase_database.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.metabase_database.by_name", "id", "metabase_database.test", "id"),
+					resource.TestCheckResourceAttr("data.metabase_database.by_name", "engine", "postgres"),
+					resource.TestCheckResourceAttr("data.metabase_database.by_id", "name", name),
+				),
+			},
+		},
+	})
+}
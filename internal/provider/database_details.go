@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// databaseEngineField is one attribute of a typed connection block and the
+// details key it is sent as.
+type databaseEngineField struct {
+	attribute   string
+	key         string
+	kind        attr.Type // types.StringType, types.Int64Type or types.BoolType
+	required    bool
+	sensitive   bool // Metabase returns it redacted
+	description string
+	allowed     []string
+}
+
+// databaseEngine is a typed connection block: an alternative to details for
+// one engine.
+type databaseEngine struct {
+	attribute string
+	engine    string
+	fields    []databaseEngineField
+}
+
+var (
+	hostField = databaseEngineField{attribute: "host", key: "host", kind: types.StringType, required: true, description: "Host name or IP address of the server"}
+	userField = databaseEngineField{attribute: "user", key: "user", kind: types.StringType, required: true, description: "User name"}
+	passField = databaseEngineField{attribute: "password", key: "password", kind: types.StringType, sensitive: true, description: "Password; or set `password` in `details_wo` to keep it out of the state"}
+	sslField  = databaseEngineField{attribute: "ssl", key: "ssl", kind: types.BoolType, description: "Whether to connect over SSL"}
+	optsField = databaseEngineField{attribute: "additional_options", key: "additional-options", kind: types.StringType, description: "Extra JDBC connection string options, as `key=value` pairs joined by `&`"}
+)
+
+func portField(defaultPort int) databaseEngineField {
+	return databaseEngineField{attribute: "port", key: "port", kind: types.Int64Type, description: fmt.Sprintf("Port of the server; the driver defaults to %d", defaultPort)}
+}
+
+// databaseEngines are the engines with a typed connection block.
+var databaseEngines = []databaseEngine{
+	{attribute: "postgres", engine: "postgres", fields: []databaseEngineField{
+		hostField, portField(5432),
+		{attribute: "dbname", key: "dbname", kind: types.StringType, required: true, description: "Database name"},
+		userField, passField, sslField,
+		{attribute: "ssl_mode", key: "ssl-mode", kind: types.StringType, description: "SSL mode when `ssl` is true", allowed: []string{"allow", "prefer", "require", "verify-ca", "verify-full"}},
+		optsField,
+	}},
+	{attribute: "mysql", engine: "mysql", fields: []databaseEngineField{
+		hostField, portField(3306),
+		{attribute: "dbname", key: "dbname", kind: types.StringType, required: true, description: "Database name"},
+		userField, passField, sslField, optsField,
+	}},
+	{attribute: "redshift", engine: "redshift", fields: []databaseEngineField{
+		hostField, portField(5439),
+		{attribute: "db", key: "db", kind: types.StringType, required: true, description: "Database name"},
+		userField, passField, optsField,
+	}},
+	{attribute: "snowflake", engine: "snowflake", fields: []databaseEngineField{
+		{attribute: "account", key: "account", kind: types.StringType, required: true, description: "Account name, e.g. \"xy12345.eu-central-1\""},
+		userField, passField,
+		{attribute: "private_key", key: "private-key-value", kind: types.StringType, sensitive: true, description: "PEM private key for key pair authentication, instead of `password`"},
+		{attribute: "warehouse", key: "warehouse", kind: types.StringType, required: true, description: "Virtual warehouse running the queries"},
+		{attribute: "db", key: "db", kind: types.StringType, required: true, description: "Database name"},
+		{attribute: "role", key: "role", kind: types.StringType, description: "Role the queries run as"},
+		optsField,
+	}},
+	{attribute: "bigquery_cloud_sdk", engine: "bigquery-cloud-sdk", fields: []databaseEngineField{
+		{attribute: "project_id", key: "project-id", kind: types.StringType, description: "Project to query; defaults to the service account's project"},
+		{attribute: "service_account_json", key: "service-account-json", kind: types.StringType, sensitive: true, description: "Service account key file content (JSON); or set `service-account-json` in `details_wo`"},
+		{attribute: "dataset_filters_type", key: "dataset-filters-type", kind: types.StringType, description: "Which datasets are synced", allowed: []string{"all", "inclusion", "exclusion"}},
+		{attribute: "dataset_filters_patterns", key: "dataset-filters-patterns", kind: types.StringType, description: "Comma-separated dataset names or wildcard patterns for `dataset_filters_type`"},
+	}},
+	{attribute: "h2", engine: "h2", fields: []databaseEngineField{
+		{attribute: "db", key: "db", kind: types.StringType, required: true, description: "Connection string, e.g. \"file:/data/app.db\""},
+	}},
+}
+
+// databaseEngineAttributes returns the typed connection blocks of the schema.
+func databaseEngineAttributes() map[string]schema.Attribute {
+	attributes := make(map[string]schema.Attribute, len(databaseEngines))
+	for _, e := range databaseEngines {
+		nested := make(map[string]schema.Attribute, len(e.fields))
+		for _, f := range e.fields {
+			switch f.kind {
+			case types.Int64Type:
+				nested[f.attribute] = schema.Int64Attribute{MarkdownDescription: f.description, Required: f.required, Optional: !f.required}
+			case types.BoolType:
+				nested[f.attribute] = schema.BoolAttribute{MarkdownDescription: f.description, Required: f.required, Optional: !f.required}
+			default:
+				a := schema.StringAttribute{MarkdownDescription: f.description, Required: f.required, Optional: !f.required, Sensitive: f.sensitive}
+				if f.allowed != nil {
+					a.Validators = []validator.String{OneOfValidator(f.allowed...)}
+				}
+				nested[f.attribute] = a
+			}
+		}
+		attributes[e.attribute] = schema.SingleNestedAttribute{
+			MarkdownDescription: fmt.Sprintf("Typed connection details for the `%s` engine, instead of `details`. Sets `engine`; secrets are redacted by Metabase and never read back.", e.engine),
+			Optional:            true,
+			Attributes:          nested,
+		}
+	}
+	return attributes
+}
+
+// engineBlocks returns the model's typed connection blocks by attribute name.
+func engineBlocks(m *terraform.DatabaseTerraformModel) map[string]*types.Object {
+	return map[string]*types.Object{
+		"postgres":           &m.Postgres,
+		"mysql":              &m.Mysql,
+		"redshift":           &m.Redshift,
+		"snowflake":          &m.Snowflake,
+		"bigquery_cloud_sdk": &m.BigqueryCloudSdk,
+		"h2":                 &m.H2,
+	}
+}
+
+// typedEngine returns the engine whose block is set and the block, or nil
+// when the connection is given as details.
+func typedEngine(m *terraform.DatabaseTerraformModel) (*databaseEngine, types.Object) {
+	blocks := engineBlocks(m)
+	for i := range databaseEngines {
+		if block := *blocks[databaseEngines[i].attribute]; !block.IsNull() {
+			return &databaseEngines[i], block
+		}
+	}
+	return nil, types.ObjectNull(nil)
+}
+
+func (e databaseEngine) attributeTypes() map[string]attr.Type {
+	attrTypes := make(map[string]attr.Type, len(e.fields))
+	for _, f := range e.fields {
+		attrTypes[f.attribute] = f.kind
+	}
+	return attrTypes
+}
+
+// redacted returns the details keys Metabase redacts.
+func (e databaseEngine) redacted() []string {
+	var keys []string
+	for _, f := range e.fields {
+		if f.sensitive {
+			keys = append(keys, f.key)
+		}
+	}
+	return keys
+}
+
+// details returns the block as details; unset attributes are omitted.
+func (e databaseEngine) details(block types.Object) map[string]any {
+	details := map[string]any{}
+	attrs := block.Attributes()
+	for _, f := range e.fields {
+		switch v := attrs[f.attribute].(type) {
+		case types.String:
+			if !v.IsNull() && !v.IsUnknown() {
+				details[f.key] = v.ValueString()
+			}
+		case types.Int64:
+			if !v.IsNull() && !v.IsUnknown() {
+				details[f.key] = v.ValueInt64()
+			}
+		case types.Bool:
+			if !v.IsNull() && !v.IsUnknown() {
+				details[f.key] = v.ValueBool()
+			}
+		}
+	}
+	return details
+}
+
+// object returns block refreshed from the reconciled details: only the
+// attributes it sets are read back, and a value of the wrong type keeps the
+// block's.
+func (e databaseEngine) object(details map[string]any, block types.Object) types.Object {
+	attrs := block.Attributes()
+	refreshed := make(map[string]attr.Value, len(attrs))
+	for _, f := range e.fields {
+		current := attrs[f.attribute]
+		refreshed[f.attribute] = current
+		v, ok := details[f.key]
+		if !ok || current.IsNull() || current.IsUnknown() {
+			continue
+		}
+		switch value := v.(type) {
+		case string:
+			switch f.kind {
+			case types.StringType:
+				refreshed[f.attribute] = types.StringValue(value)
+			case types.Int64Type:
+				if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+					refreshed[f.attribute] = types.Int64Value(n)
+				}
+			}
+		case float64:
+			if f.kind == types.Int64Type {
+				refreshed[f.attribute] = types.Int64Value(int64(value))
+			}
+		case bool:
+			if f.kind == types.BoolType {
+				refreshed[f.attribute] = types.BoolValue(value)
+			}
+		}
+	}
+	return types.ObjectValueMust(e.attributeTypes(), refreshed)
+}
+
+// hasUnknown reports whether any attribute of the block is unknown.
+func hasUnknown(block types.Object) bool {
+	if block.IsUnknown() {
+		return true
+	}
+	for _, v := range block.Attributes() {
+		if v.IsUnknown() {
+			return true
+		}
+	}
+	return false
+}
+
+// databaseConnection returns the engine and details JSON to send: details,
+// or the typed block encoded, with secrets (from details_wo) merged over
+// them. Custom schedules need Metabase's "let-user-control-scheduling" flag:
+// a typed block sets it when customSchedules (see schedulesConfigured).
+func databaseConnection(m *terraform.DatabaseTerraformModel, secrets map[string]any, customSchedules bool) (string, string, error) {
+	e, block := typedEngine(m)
+	if e == nil && len(secrets) == 0 {
+		return m.Engine.ValueString(), m.Details.ValueString(), nil
+	}
+
+	engine, details := m.Engine.ValueString(), map[string]any{}
+	if e == nil {
+		if err := json.Unmarshal([]byte(m.Details.ValueString()), &details); err != nil {
+			return "", "", fmt.Errorf("invalid details JSON: %w", err)
+		}
+	} else {
+		engine, details = e.engine, e.details(block)
+		if customSchedules {
+			details["let-user-control-scheduling"] = true
+		}
+	}
+	maps.Copy(details, secrets)
+	b, err := json.Marshal(details)
+	if err != nil {
+		return "", "", err
+	}
+	return engine, string(b), nil
+}
+
+// databaseSecrets decodes details_wo. Write-only attributes are only set in
+// the config: read it from there, never from the plan or state.
+func databaseSecrets(detailsWO types.String) (map[string]any, error) {
+	if detailsWO.IsNull() || detailsWO.IsUnknown() {
+		return nil, nil
+	}
+	var secrets map[string]any
+	if err := json.Unmarshal([]byte(detailsWO.ValueString()), &secrets); err != nil {
+		return nil, fmt.Errorf("invalid details_wo JSON: %w", err)
+	}
+	return secrets, nil
+}
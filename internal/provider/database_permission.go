@@ -5,18 +5,24 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
 // splitEdgeID splits a "left:right" composite id.
@@ -35,10 +41,11 @@ func NewDatabasePermission() resource.Resource {
 		TypeName: "database_permission",
 		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
 			databasePermission.repository = repositories.NewDatabasePermissionRepository(client)
+			databasePermission.tableRepository = repositories.NewTableRepository(client)
 		},
 		GetSchema: func(ctx context.Context) schema.Schema {
 			return schema.Schema{
-				MarkdownDescription: "Query-building access of one permission group on one database (an edge of the Metabase permissions graph). On OSS this is the real data-access control (view-data is always unrestricted and is not managed).",
+				MarkdownDescription: "Data access of one permission group on one database (an edge of the Metabase permissions graph), written in a single graph update. On OSS only `create_queries` applies and is the real data-access control (view-data is always unrestricted). On Enterprise, `view_data`, `download`/`download_schemas`, `data_model` and `details` can be managed too; each is left untouched while unset, and import reads `create_queries` only (the others are written on the next apply). Requires Metabase 50 or later (the Enterprise attributes need the `advanced_permissions` feature).",
 				Attributes: map[string]schema.Attribute{
 					"id": schema.StringAttribute{
 						Computed:            true,
@@ -56,12 +63,56 @@ func NewDatabasePermission() resource.Resource {
 						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
 					},
 					"create_queries": schema.StringAttribute{
-						MarkdownDescription: "Query-building access: \"no\", \"query-builder\", or \"query-builder-and-native\".",
+						MarkdownDescription: "Query-building access on the whole database: \"no\" (default), \"query-builder\", or \"query-builder-and-native\". Reads \"granular\" when `create_queries_schemas` is used instead.",
 						Optional:            true,
 						Computed:            true,
-						Default:             stringdefault.StaticString("no"),
 						Validators:          []validator.String{OneOfValidator("no", "query-builder", "query-builder-and-native")},
 					},
+					"create_queries_schemas": schema.MapNestedAttribute{
+						MarkdownDescription: "Granular query-building access, keyed by schema name (`\"\"` for engines without schemas). Schemas not listed get no access; native queries can only be granted on the whole database. Schemas and table IDs are checked against the tables Metabase has synced. Conflicts with `create_queries`.",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"level": schema.StringAttribute{
+									MarkdownDescription: "Access to every table of the schema: \"no\" or \"query-builder\". Conflicts with `tables`.",
+									Optional:            true,
+									Validators:          []validator.String{OneOfValidator("no", "query-builder")},
+								},
+								"tables": schema.MapAttribute{
+									MarkdownDescription: "Access per table of the schema, keyed by table ID: \"no\" or \"query-builder\". Tables not listed get no access. Conflicts with `level`.",
+									ElementType:         types.StringType,
+									Optional:            true,
+									Validators:          []validator.Map{MapValuesOneOfValidator("no", "query-builder")},
+								},
+							},
+						},
+					},
+					"view_data": schema.StringAttribute{
+						MarkdownDescription: "Enterprise. Data the group can view: \"unrestricted\" or \"blocked\". \"impersonated\" and \"sandboxed\" can't be set here: they are set by `metabase_connection_impersonation` and `metabase_sandbox` along with their policies. Writing \"unrestricted\" or \"blocked\" replaces the database's table-level view-data, removing the group's sandboxes on it: planning fails while it has any, so leave `view_data` unset then. \"blocked\" requires `create_queries = \"no\"`. Left untouched on destroy.",
+						Optional:            true,
+						Validators:          []validator.String{OneOfValidator("unrestricted", "blocked", "impersonated", "sandboxed")},
+					},
+					"download": schema.StringAttribute{
+						MarkdownDescription: "Enterprise. Download access to query results on the whole database: \"none\", \"limited\" (up to 10,000 rows) or \"full\". Conflicts with `download_schemas`.",
+						Optional:            true,
+						Validators:          []validator.String{OneOfValidator("none", "limited", "full")},
+					},
+					"download_schemas": schema.MapAttribute{
+						MarkdownDescription: "Enterprise. Download access per schema, keyed by schema name: \"none\", \"limited\" or \"full\". Conflicts with `download`.",
+						ElementType:         types.StringType,
+						Optional:            true,
+						Validators:          []validator.Map{MapValuesOneOfValidator("none", "limited", "full")},
+					},
+					"data_model": schema.StringAttribute{
+						MarkdownDescription: "Enterprise. Access to the table metadata editor: \"all\" or \"none\".",
+						Optional:            true,
+						Validators:          []validator.String{OneOfValidator("all", "none")},
+					},
+					"details": schema.StringAttribute{
+						MarkdownDescription: "Enterprise. Access to the database connection details: \"yes\" or \"no\".",
+						Optional:            true,
+						Validators:          []validator.String{OneOfValidator("yes", "no")},
+					},
 				},
 			}
 		},
@@ -72,7 +123,13 @@ func NewDatabasePermission() resource.Resource {
 				return
 			}
 
-			err := databasePermission.repository.Set(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString(), plan.CreateQueries.ValueString())
+			input, diags := databasePermissionInput(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			err := databasePermission.repository.Set(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString(), input)
 			if err != nil {
 				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to set database permission: %s", err))
 				return
@@ -94,7 +151,7 @@ func NewDatabasePermission() resource.Resource {
 				return
 			}
 
-			createQueries, found, err := databasePermission.repository.Get(ctx, groupId, databaseId)
+			permission, found, err := databasePermission.repository.Get(ctx, groupId, databaseId)
 			if err != nil {
 				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get database permission: %s", err))
 				return
@@ -104,12 +161,7 @@ func NewDatabasePermission() resource.Resource {
 				return
 			}
 
-			result := terraform.DatabasePermissionTerraformModel{
-				Id:            idOf(groupId, databaseId),
-				GroupId:       stringValue(groupId),
-				DatabaseId:    stringValue(databaseId),
-				CreateQueries: stringValue(createQueries),
-			}
+			result := terraform.CreateDatabasePermissionTerraformModel(groupId, databaseId, permission, state)
 			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
 		},
 		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -119,7 +171,13 @@ func NewDatabasePermission() resource.Resource {
 				return
 			}
 
-			err := databasePermission.repository.Set(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString(), plan.CreateQueries.ValueString())
+			input, diags := databasePermissionInput(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			err := databasePermission.repository.Set(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString(), input)
 			if err != nil {
 				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update database permission: %s", err))
 				return
@@ -134,8 +192,19 @@ func NewDatabasePermission() resource.Resource {
 				return
 			}
 
-			// Revoke = "no"; the edge itself can't be removed.
-			err := databasePermission.repository.Set(ctx, state.GroupId.ValueString(), state.DatabaseId.ValueString(), "no")
+			// Revoke every managed grant; the edge itself can't be removed.
+			// view-data is a restriction, not a grant: it is left as is.
+			revoked := dtos.DatabasePermissionDTO{CreateQueries: "no"}
+			if !state.Download.IsNull() || !state.DownloadSchemas.IsNull() {
+				revoked.Download = stringPtr("none")
+			}
+			if !state.DataModel.IsNull() {
+				revoked.DataModel = stringPtr("none")
+			}
+			if !state.Details.IsNull() {
+				revoked.Details = stringPtr("no")
+			}
+			err := databasePermission.repository.Set(ctx, state.GroupId.ValueString(), state.DatabaseId.ValueString(), revoked)
 			if err != nil {
 				resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("Unable to revoke database permission: %s", err))
 				return
@@ -148,8 +217,193 @@ func NewDatabasePermission() resource.Resource {
 	return databasePermission
 }
 
+// databasePermissionInput builds the edge to write from the plan.
+func databasePermissionInput(ctx context.Context, plan terraform.DatabasePermissionTerraformModel) (dtos.DatabasePermissionDTO, diag.Diagnostics) {
+	input := dtos.DatabasePermissionDTO{
+		CreateQueries: plan.CreateQueries.ValueString(),
+		ViewData:      plan.ViewData.ValueStringPointer(),
+		Download:      plan.Download.ValueStringPointer(),
+		DataModel:     plan.DataModel.ValueStringPointer(),
+		Details:       plan.Details.ValueStringPointer(),
+	}
+	var diags diag.Diagnostics
+	if !plan.CreateQueriesSchemas.IsNull() {
+		var schemas map[string]terraform.SchemaCreateQueriesTerraformModel
+		diags.Append(plan.CreateQueriesSchemas.ElementsAs(ctx, &schemas, false)...)
+		input.CreateQueriesSchemas = map[string]dtos.SchemaPermissionDTO{}
+		for schema, p := range schemas {
+			schemaInput := dtos.SchemaPermissionDTO{Level: p.Level.ValueString()}
+			if !p.Tables.IsNull() {
+				schemaInput.Tables = map[string]string{}
+				diags.Append(p.Tables.ElementsAs(ctx, &schemaInput.Tables, false)...)
+			}
+			input.CreateQueriesSchemas[schema] = schemaInput
+		}
+	}
+	if !plan.DownloadSchemas.IsNull() {
+		input.DownloadSchemas = map[string]string{}
+		diags.Append(plan.DownloadSchemas.ElementsAs(ctx, &input.DownloadSchemas, false)...)
+	}
+	return input, diags
+}
+
+var _ resource.ResourceWithValidateConfig = &DatabasePermission{}
+
+// ValidateConfig implements resource.ResourceWithValidateConfig.
+func (d *DatabasePermission) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config terraform.DatabasePermissionTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Download.IsNull() && !config.DownloadSchemas.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("download_schemas"), "Invalid Attribute Combination", "Set either `download` (whole database) or `download_schemas` (per schema), not both.")
+	}
+	if !config.CreateQueries.IsNull() && !config.CreateQueriesSchemas.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("create_queries_schemas"), "Invalid Attribute Combination", "Set either `create_queries` (whole database) or `create_queries_schemas` (per schema), not both.")
+	}
+	if repositories.IsPolicyViewData(config.ViewData.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("view_data"), "Invalid Attribute Value", fmt.Sprintf("`view_data = %q` can't be set here: manage it with `metabase_connection_impersonation` (\"impersonated\") or `metabase_sandbox` (\"sandboxed\"), which set it along with their policies, and leave `view_data` unset.", config.ViewData.ValueString()))
+	}
+	// Metabase only lets groups that can view the data query it.
+	if config.ViewData.ValueString() == "blocked" {
+		if !config.CreateQueries.IsUnknown() && !config.CreateQueries.IsNull() && config.CreateQueries.ValueString() != "no" {
+			resp.Diagnostics.AddAttributeError(path.Root("create_queries"), "Invalid Attribute Combination", fmt.Sprintf("`view_data = \"blocked\"` requires `create_queries = \"no\"`, got %q.", config.CreateQueries.ValueString()))
+		}
+		if !config.CreateQueriesSchemas.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("create_queries_schemas"), "Invalid Attribute Combination", "`view_data = \"blocked\"` can't be combined with `create_queries_schemas`.")
+		}
+	}
+
+	if config.CreateQueriesSchemas.IsNull() || config.CreateQueriesSchemas.IsUnknown() {
+		return
+	}
+	if len(config.CreateQueriesSchemas.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("create_queries_schemas"), "Invalid Attribute Value", "List at least one schema; for no access on the whole database, set `create_queries = \"no\"`.")
+	}
+	for schema, element := range config.CreateQueriesSchemas.Elements() {
+		var p terraform.SchemaCreateQueriesTerraformModel
+		object, ok := element.(types.Object)
+		if !ok || object.IsUnknown() {
+			continue
+		}
+		resp.Diagnostics.Append(object.As(ctx, &p, basetypes.ObjectAsOptions{})...)
+		if p.Level.IsUnknown() || p.Tables.IsUnknown() {
+			continue
+		}
+		if p.Level.IsNull() == p.Tables.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("create_queries_schemas").AtMapKey(schema), "Invalid Attribute Combination", "Set exactly one of `level` (whole schema) or `tables` (per table).")
+		}
+	}
+}
+
+var _ resource.ResourceWithModifyPlan = &DatabasePermission{}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. It defaults
+// create_queries ("no", or "granular" with create_queries_schemas), checks
+// the granular schemas and tables against the database, and refuses to write
+// a database-level view_data over the group's sandboxes.
+func (d *DatabasePermission) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return // destroy
+	}
+	var config, plan terraform.DatabasePermissionTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.CreateQueries.IsNull() {
+		switch {
+		case config.CreateQueriesSchemas.IsUnknown():
+			plan.CreateQueries = types.StringUnknown()
+		case config.CreateQueriesSchemas.IsNull():
+			plan.CreateQueries = types.StringValue("no")
+		default:
+			plan.CreateQueries = types.StringValue("granular")
+		}
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	}
+
+	if !req.Plan.Raw.Equal(req.State.Raw) {
+		d.checkSandboxes(ctx, plan, &resp.Diagnostics)
+	}
+
+	if d.tableRepository == nil || plan.DatabaseId.IsUnknown() || plan.CreateQueriesSchemas.IsNull() || plan.CreateQueriesSchemas.IsUnknown() {
+		return
+	}
+	var schemas map[string]terraform.SchemaCreateQueriesTerraformModel
+	resp.Diagnostics.Append(plan.CreateQueriesSchemas.ElementsAs(ctx, &schemas, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	d.validateTables(ctx, plan.DatabaseId.ValueString(), schemas, &resp.Diagnostics)
+}
+
+// checkSandboxes reports a database-level view_data about to be written while
+// the group has sandboxed tables on the database: the write would remove them.
+func (d *DatabasePermission) checkSandboxes(ctx context.Context, plan terraform.DatabasePermissionTerraformModel, diags *diag.Diagnostics) {
+	if d.repository == nil || plan.ViewData.IsNull() || plan.ViewData.IsUnknown() || repositories.IsPolicyViewData(plan.ViewData.ValueString()) ||
+		plan.GroupId.IsUnknown() || plan.DatabaseId.IsUnknown() {
+		return
+	}
+	current, found, err := d.repository.Get(ctx, plan.GroupId.ValueString(), plan.DatabaseId.ValueString())
+	var unsupported *metabase.UnsupportedError
+	if errors.As(err, &unsupported) {
+		return // reported on apply
+	}
+	if err != nil {
+		diags.AddError("Validation Error", fmt.Sprintf("Unable to get database permission: %s", err))
+		return
+	}
+	if found && current.ViewData != nil && *current.ViewData == "sandboxed" {
+		diags.AddAttributeError(path.Root("view_data"), "Sandboxes Would Be Removed", fmt.Sprintf("Group %s has sandboxed tables on database %s; writing `view_data = %q` would remove them. Leave `view_data` unset while the group has `metabase_sandbox` resources on this database.", plan.GroupId.ValueString(), plan.DatabaseId.ValueString(), plan.ViewData.ValueString()))
+	}
+}
+
+// validateTables reports schemas and table IDs of create_queries_schemas that
+// the database does not have (as last synced by Metabase).
+func (d *DatabasePermission) validateTables(ctx context.Context, databaseId string, schemas map[string]terraform.SchemaCreateQueriesTerraformModel, diags *diag.Diagnostics) {
+	tables, err := d.tableRepository.List(ctx, databaseId)
+	if err != nil {
+		diags.AddError("Validation Error", fmt.Sprintf("Unable to list the tables of database %s: %s", databaseId, err))
+		return
+	}
+	tableSchemas := map[string]map[string]bool{}
+	for _, t := range tables {
+		schema := ""
+		if t.Schema != nil {
+			schema = *t.Schema
+		}
+		if tableSchemas[schema] == nil {
+			tableSchemas[schema] = map[string]bool{}
+		}
+		tableSchemas[schema][strconv.Itoa(t.Id)] = true
+	}
+
+	for schema, p := range schemas {
+		schemaPath := path.Root("create_queries_schemas").AtMapKey(schema)
+		tableIds, ok := tableSchemas[schema]
+		if !ok {
+			diags.AddAttributeError(schemaPath, "Unknown Schema", fmt.Sprintf("Database %s has no synced schema %q.", databaseId, schema))
+			continue
+		}
+		if p.Tables.IsNull() || p.Tables.IsUnknown() {
+			continue
+		}
+		for tableId := range p.Tables.Elements() {
+			if !tableIds[tableId] {
+				diags.AddAttributeError(schemaPath.AtName("tables").AtMapKey(tableId), "Unknown Table", fmt.Sprintf("Schema %q of database %s has no synced table with ID %s.", schema, databaseId, tableId))
+			}
+		}
+	}
+}
+
 // DatabasePermission defines the resource implementation.
 type DatabasePermission struct {
 	*BaseResource
-	repository *repositories.DatabasePermissionRepository
+	repository      *repositories.DatabasePermissionRepository
+	tableRepository *repositories.TableRepository
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"regexp"
 	"testing"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
@@ -16,7 +17,8 @@ import (
 )
 
 // testAccCheckDatabasePermissionRevoked asserts that after destroy the edge is
-// either gone (its group was deleted) or reset to no query access ("no").
+// either gone (its group was deleted) or reset to no query access ("no"), with
+// any managed download access revoked.
 func testAccCheckDatabasePermissionRevoked(s *terraform.State) error {
 	repo := repositories.NewDatabasePermissionRepository(newTestMetabaseClient())
 	for _, rs := range s.RootModule().Resources {
@@ -27,12 +29,18 @@ func testAccCheckDatabasePermissionRevoked(s *terraform.State) error {
 		if err != nil {
 			return err
 		}
-		createQueries, found, err := repo.Get(context.Background(), groupId, databaseId)
+		permission, found, err := repo.Get(context.Background(), groupId, databaseId)
 		if err != nil {
 			return err
 		}
-		if found && createQueries != "no" {
-			return fmt.Errorf("database permission %s still grants %q after destroy", rs.Primary.ID, createQueries)
+		if !found {
+			continue
+		}
+		if permission.CreateQueries != "no" {
+			return fmt.Errorf("database permission %s still grants %q after destroy", rs.Primary.ID, permission.CreateQueries)
+		}
+		if _, managed := rs.Primary.Attributes["download"]; managed && permission.Download != nil && *permission.Download != "none" {
+			return fmt.Errorf("database permission %s still grants download %q after destroy", rs.Primary.ID, *permission.Download)
 		}
 	}
 	return nil
@@ -122,3 +130,177 @@ resource "metabase_database_permission" "other" {
 }
 `, groupName, createQueries)
 }
+
+func TestAccDatabasePermissionResource_Advanced(t *testing.T) {
+	name := fmt.Sprintf("Test advanced graph group %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckTokenFeature(t, "advanced_permissions")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDatabasePermissionRevoked,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabasePermissionAdvancedConfig(name, "full", "no"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_database_permission.test", "create_queries", "query-builder"),
+					resource.TestCheckResourceAttr("metabase_database_permission.test", "view_data", "unrestricted"),
+					resource.TestCheckResourceAttr("metabase_database_permission.test", "download", "full"),
+					resource.TestCheckResourceAttr("metabase_database_permission.test", "data_model", "all"),
+					resource.TestCheckResourceAttr("metabase_database_permission.test", "details", "no"),
+				),
+			},
+			// Import reads create_queries only: the optional levels are adopted
+			// from the configuration on the next apply.
+			{
+				ResourceName:            "metabase_database_permission.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"view_data", "download", "data_model", "details"},
+			},
+			// Both levels change in one in-place update.
+			{
+				Config: testAccDatabasePermissionAdvancedConfig(name, "limited", "yes"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_database_permission.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_database_permission.test", "download", "limited"),
+					resource.TestCheckResourceAttr("metabase_database_permission.test", "details", "yes"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDatabasePermissionAdvancedConfig(groupName, download, details string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "a" {
+  name                = "%[1]s db"
+  engine              = "postgres"
+  deletion_protection = false
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+
+resource "metabase_permission_group" "test" {
+  name = "%[1]s"
+}
+
+resource "metabase_database_permission" "test" {
+  group_id       = metabase_permission_group.test.id
+  database_id    = metabase_database.a.id
+  create_queries = "query-builder"
+  view_data      = "unrestricted"
+  download       = "%[2]s"
+  data_model     = "all"
+  details        = "%[3]s"
+}
+`, groupName, download, details)
+}
+
+// TestAccDatabasePermissionResource_GranularValidation checks the
+// create_queries_schemas checks (which tables exist depends on when schema
+// sync finishes, so only the failures are asserted).
+func TestAccDatabasePermissionResource_GranularValidation(t *testing.T) {
+	name := fmt.Sprintf("Test granular graph group %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabasePermissionGranularConfig(name, `
+  create_queries = "query-builder"
+  create_queries_schemas = {
+    public = { level = "query-builder" }
+  }`),
+				ExpectError: regexp.MustCompile("Set either `create_queries`"),
+			},
+			{
+				Config: testAccDatabasePermissionGranularConfig(name, `
+  create_queries_schemas = {
+    public = {
+      level  = "query-builder"
+      tables = { "1" = "query-builder" }
+    }
+  }`),
+				ExpectError: regexp.MustCompile("Set exactly one of `level`"),
+			},
+			// The database is known only after its creation: validated on apply.
+			{
+				Config: testAccDatabasePermissionGranularConfig(name, ""),
+			},
+			{
+				Config: testAccDatabasePermissionGranularConfig(name, `
+  create_queries_schemas = {
+    no_such_schema = { level = "query-builder" }
+  }`),
+				ExpectError: regexp.MustCompile("no synced schema"),
+			},
+		},
+	})
+}
+
+// TestAccDatabasePermissionResource_PolicyViewData checks that the view-data
+// levels set by the policy resources are refused.
+func TestAccDatabasePermissionResource_PolicyViewData(t *testing.T) {
+	name := fmt.Sprintf("Test policy view-data group %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabasePermissionGranularConfig(name, `
+  create_queries = "no"
+  view_data      = "sandboxed"`),
+				ExpectError: regexp.MustCompile("metabase_sandbox"),
+			},
+			{
+				Config: testAccDatabasePermissionGranularConfig(name, `
+  create_queries = "no"
+  view_data      = "impersonated"`),
+				ExpectError: regexp.MustCompile("metabase_connection_impersonation"),
+			},
+		},
+	})
+}
+
+func testAccDatabasePermissionGranularConfig(groupName, access string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "a" {
+  name                = "%[1]s db"
+  engine              = "postgres"
+  deletion_protection = false
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+
+resource "metabase_permission_group" "test" {
+  name = "%[1]s"
+}
+
+resource "metabase_database_permission" "test" {
+  group_id    = metabase_permission_group.test.id
+  database_id = metabase_database.a.id
+%[2]s
+}
+`, groupName, access)
+}
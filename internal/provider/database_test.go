@@ -8,13 +8,19 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	models "github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 // These tests target the local docker "sample-db" postgres (see docker-compose).
@@ -38,6 +44,25 @@ func testAccCheckDatabaseDestroyed(s *terraform.State) error {
 	return nil
 }
 
+// testAccCheckDatabaseSynced asserts the initial sync is done, as wait_for_sync
+// promises.
+func testAccCheckDatabaseSynced(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		database, err := repositories.NewDatabaseRepository(newTestMetabaseClient()).Get(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if database.InitialSyncStatus != "complete" {
+			return fmt.Errorf("expected the initial sync of database %s to be complete, got %q", rs.Primary.ID, database.InitialSyncStatus)
+		}
+		return nil
+	}
+}
+
 func TestAccDatabaseResource(t *testing.T) {
 	name := fmt.Sprintf("Test database %d", rand.Int())
 
@@ -96,3 +121,356 @@ resource "metabase_database" "test" {
 }
 `, name)
 }
+
+// TestAccDatabaseResource_WaitForSync checks that create returns once the
+// initial sync is complete.
+func TestAccDatabaseResource_WaitForSync(t *testing.T) {
+	name := fmt.Sprintf("Test database sync %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDatabaseDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                  = "%s"
+  engine                = "postgres"
+  deletion_protection   = false
+  wait_for_sync         = true
+  wait_for_sync_timeout = "5m"
+  sync_on_change        = true
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+`, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_database.test", "wait_for_sync", "true"),
+					resource.TestCheckResourceAttr("metabase_database.test", "sync_on_change", "true"),
+					testAccCheckDatabaseSynced("metabase_database.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatabaseResource_Schedules(t *testing.T) {
+	name := fmt.Sprintf("Test database schedules %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDatabaseDestroyed,
+		Steps: []resource.TestStep{
+			// Nightly sync, weekly scan; an equivalent cron spelling is not drift.
+			{
+				Config: testAccDatabaseResourceSchedulesConfig(name, `
+  schedules = {
+    metadata_sync      = "0 0 2 * * ?"
+    cache_field_values = "0 30 3 ? * MON *"
+  }
+  auto_run_queries = false
+  refingerprint    = true
+  settings         = jsonencode({ "database-enable-actions" = true })
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_database.test", "schedules.metadata_sync", "0 0 2 * * ?"),
+					resource.TestCheckResourceAttr("metabase_database.test", "schedules.cache_field_values", "0 30 3 ? * MON *"),
+					resource.TestCheckResourceAttr("metabase_database.test", "is_full_sync", "true"),
+					resource.TestCheckResourceAttr("metabase_database.test", "auto_run_queries", "false"),
+					resource.TestCheckResourceAttr("metabase_database.test", "refingerprint", "true"),
+				),
+			},
+			// Turning the scheduled scan off drops its schedule in place.
+			{
+				Config: testAccDatabaseResourceSchedulesConfig(name, `
+  schedules = {
+    metadata_sync = "0 0 2 * * ?"
+  }
+  is_full_sync     = false
+  is_on_demand     = true
+  auto_run_queries = false
+  refingerprint    = true
+  settings         = jsonencode({})
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_database.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("metabase_database.test", "schedules.cache_field_values"),
+					resource.TestCheckResourceAttr("metabase_database.test", "is_full_sync", "false"),
+					resource.TestCheckResourceAttr("metabase_database.test", "is_on_demand", "true"),
+					resource.TestCheckResourceAttr("metabase_database.test", "settings", "{}"),
+				),
+			},
+			{
+				ResourceName:            "metabase_database.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"details", "redacted_attributes", "deletion_protection", "schedules.metadata_sync", "settings"},
+			},
+		},
+	})
+}
+
+func testAccDatabaseResourceSchedulesConfig(name string, options string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                = "%s"
+  engine              = "postgres"
+  deletion_protection = false
+  details = jsonencode({
+    host                          = "sample-db"
+    port                          = 5432
+    dbname                        = "sampledb"
+    user                          = "sampleuser"
+    password                      = "samplepass"
+    ssl                           = false
+    "let-user-control-scheduling" = true
+  })
+%s}
+`, name, options)
+}
+
+func TestAccDatabaseResource_ValidateConnection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDatabaseDestroyed,
+		Steps: []resource.TestStep{
+			// A wrong password fails the plan, before anything is created.
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                = "Test database invalid %d"
+  engine              = "postgres"
+  deletion_protection = false
+  validate_connection = true
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "wrong-password"
+    ssl      = false
+  })
+}
+`, rand.Int()),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Connection`),
+			},
+			// The right password passes the test and creates the database.
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                = "Test database valid %d"
+  engine              = "postgres"
+  deletion_protection = false
+  validate_connection = true
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+`, rand.Int()),
+				Check: resource.TestCheckResourceAttr("metabase_database.test", "validate_connection", "true"),
+			},
+		},
+	})
+}
+
+func TestAccDatabaseResource_Typed(t *testing.T) {
+	name := fmt.Sprintf("Test database typed %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDatabaseDestroyed,
+		Steps: []resource.TestStep{
+			// engine comes from the block; the redacted password is not drift.
+			{
+				Config: testAccDatabaseResourceTypedConfig(name, "sampledb"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_database.test", "engine", "postgres"),
+					resource.TestCheckResourceAttr("metabase_database.test", "postgres.port", "5432"),
+					resource.TestCheckNoResourceAttr("metabase_database.test", "details"),
+				),
+			},
+			// Changing a connection field updates in place.
+			{
+				Config: testAccDatabaseResourceTypedConfig(name, "postgres"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_database.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("metabase_database.test", "postgres.dbname", "postgres"),
+			},
+			// details and a typed block are exclusive.
+			{
+				Config: testAccProviderConfig() + `
+resource "metabase_database" "invalid" {
+  name    = "invalid"
+  engine  = "postgres"
+  details = jsonencode({ host = "sample-db" })
+  postgres = {
+    host   = "sample-db"
+    dbname = "sampledb"
+    user   = "sampleuser"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
+
+func testAccDatabaseResourceTypedConfig(name string, dbname string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                = "%s"
+  deletion_protection = false
+  postgres = {
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "%s"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  }
+}
+`, name, dbname)
+}
+
+func TestAccDatabaseResource_WriteOnlySecrets(t *testing.T) {
+	name := fmt.Sprintf("Test database write-only %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks:   []tfversion.TerraformVersionCheck{tfversion.SkipBelow(tfversion.Version1_11_0)},
+		CheckDestroy:             testAccCheckDatabaseDestroyed,
+		Steps: []resource.TestStep{
+			// The password reaches Metabase (which tests the connection) but not the state.
+			{
+				Config: testAccDatabaseResourceWriteOnlyConfig(name, 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("metabase_database.test", "postgres.password"),
+					resource.TestCheckNoResourceAttr("metabase_database.test", "details_wo"),
+					resource.TestCheckResourceAttr("metabase_database.test", "details_wo_version", "1"),
+				),
+			},
+			// Bumping the version resends the secrets in place.
+			{
+				Config: testAccDatabaseResourceWriteOnlyConfig(name, 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_database.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("metabase_database.test", "details_wo_version", "2"),
+			},
+		},
+	})
+}
+
+func testAccDatabaseResourceWriteOnlyConfig(name string, version int) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_database" "test" {
+  name                = "%s"
+  deletion_protection = false
+  validate_connection = true
+  postgres = {
+    host   = "sample-db"
+    port   = 5432
+    dbname = "sampledb"
+    user   = "sampleuser"
+  }
+  details_wo         = jsonencode({ password = "samplepass" })
+  details_wo_version = %d
+}
+`, name, version)
+}
+
+func TestDatabaseEngineDetails(t *testing.T) {
+	var postgres databaseEngine
+	for _, e := range databaseEngines {
+		if e.engine == "postgres" {
+			postgres = e
+		}
+	}
+	values := map[string]attr.Value{}
+	for name, kind := range postgres.attributeTypes() {
+		switch kind {
+		case types.Int64Type:
+			values[name] = types.Int64Null()
+		case types.BoolType:
+			values[name] = types.BoolNull()
+		default:
+			values[name] = types.StringNull()
+		}
+	}
+	values["host"] = types.StringValue("db.example.com")
+	values["port"] = types.Int64Value(5432)
+	values["dbname"] = types.StringValue("analytics")
+	values["user"] = types.StringValue("metabase")
+	values["password"] = types.StringValue("secret")
+	values["ssl_mode"] = types.StringValue("require")
+	block := types.ObjectValueMust(postgres.attributeTypes(), values)
+
+	details := postgres.details(block)
+	want := map[string]any{"host": "db.example.com", "port": int64(5432), "dbname": "analytics", "user": "metabase", "password": "secret", "ssl-mode": "require"}
+	if fmt.Sprint(details) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, details)
+	}
+	if fmt.Sprint(postgres.redacted()) != "[password]" {
+		t.Errorf("expected the password to be redacted, got %v", postgres.redacted())
+	}
+
+	// Read back: set attributes take the API values, with the JSON number and
+	// a string port converted; unset ones stay null.
+	refreshed := postgres.object(map[string]any{"host": "db2.example.com", "port": "6543", "dbname": "analytics", "user": "metabase", "password": "secret", "ssl": true}, block).Attributes()
+	if refreshed["host"].(types.String).ValueString() != "db2.example.com" || refreshed["port"].(types.Int64).ValueInt64() != 6543 {
+		t.Errorf("expected host/port drift, got %s %s", refreshed["host"], refreshed["port"])
+	}
+	if !refreshed["ssl"].IsNull() {
+		t.Errorf("expected unset ssl to stay null, got %s", refreshed["ssl"])
+	}
+	if refreshed["ssl_mode"].(types.String).ValueString() != "require" {
+		t.Errorf("expected ssl_mode missing from the API to keep its value, got %s", refreshed["ssl_mode"])
+	}
+
+	// The scheduling flag follows the configured schedules, not the computed
+	// ones always known in the plan and state.
+	m := &models.DatabaseTerraformModel{
+		Postgres: block,
+		Schedules: types.ObjectValueMust(models.DatabaseSchedulesType.AttrTypes, map[string]attr.Value{
+			"metadata_sync":      types.StringValue("0 0 * * * ? *"),
+			"cache_field_values": types.StringNull(),
+		}),
+	}
+	for _, customSchedules := range []bool{false, true} {
+		_, encoded, err := databaseConnection(m, nil, customSchedules)
+		if err != nil {
+			t.Fatalf("databaseConnection failed: %s", err)
+		}
+		if strings.Contains(encoded, "let-user-control-scheduling") != customSchedules {
+			t.Errorf("custom schedules %t: unexpected details %s", customSchedules, encoded)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func NewDatabasesDataSource() datasource.DataSource {
+	databases := &DatabasesDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "databases",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			databases.repository = repositories.NewDatabaseRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Lists database connections, optionally filtered by engine and name. Connection details are not exposed.",
+				Attributes: map[string]schema.Attribute{
+					"engine": schema.StringAttribute{
+						MarkdownDescription: "Only list databases with this engine, e.g. \"postgres\".",
+						Optional:            true,
+					},
+					"name_regex": schema.StringAttribute{
+						MarkdownDescription: "Only list databases whose name matches this regular expression.",
+						Optional:            true,
+						Validators:          []validator.String{RegexValidator()},
+					},
+					"databases": schema.ListNestedAttribute{
+						MarkdownDescription: "Matching databases, ordered by ID",
+						Computed:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									MarkdownDescription: "Database ID",
+									Computed:            true,
+								},
+								"name": schema.StringAttribute{
+									MarkdownDescription: "Name of the database",
+									Computed:            true,
+								},
+								"engine": schema.StringAttribute{
+									MarkdownDescription: "Engine of the database",
+									Computed:            true,
+								},
+							},
+						},
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.DatabasesDataSourceTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			nameRegex, err := optionalRegex(config.NameRegex)
+			if err != nil {
+				resp.Diagnostics.AddError("Invalid name_regex", err.Error())
+				return
+			}
+
+			list, err := databases.repository.List(ctx)
+			if err != nil {
+				resp.Diagnostics.AddError("List Error", fmt.Sprintf("Unable to list databases: %s", err))
+				return
+			}
+			sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+
+			config.Databases = []terraform.DatabaseDataSourceTerraformModel{}
+			for i := range list {
+				if !config.Engine.IsNull() && list[i].Engine != config.Engine.ValueString() {
+					continue
+				}
+				if matchesRegex(nameRegex, list[i].Name) {
+					config.Databases = append(config.Databases, terraform.CreateDatabaseDataSourceTerraformModelFromDTO(&list[i]))
+				}
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+		},
+	}
+
+	databases.BaseDataSource = baseDataSource
+
+	return databases
+}
+
+// DatabasesDataSource defines the data source implementation.
+type DatabasesDataSource struct {
+	*BaseDataSource
+	repository *repositories.DatabaseRepository
+}
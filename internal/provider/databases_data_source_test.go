@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccDatabasesDataSource lists databases filtered by engine and name.
+func TestAccDatabasesDataSource(t *testing.T) {
+	name := fmt.Sprintf("Test databases ds %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseResourceConfig(name) + fmt.Sprintf(`
+data "metabase_databases" "postgres" {
+  engine     = "postgres"
+  name_regex = "^%[1]s$"
+  depends_on = [metabase_database.test]
+}
+
+data "metabase_databases" "mysql" {
+  engine     = "mysql"
+  name_regex = "^%[1]s$"
+  depends_on = [metabase_database.test]
+}
+`, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.metabase_databases.postgres", "databases.#", "1"),
+					resource.TestCheckResourceAttrPair("data.metabase_databases.postgres", "databases.0.id", "metabase_database.test", "id"),
+					resource.TestCheckResourceAttr("data.metabase_databases.mysql", "databases.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fkSemanticType is the semantic type of foreign keys, the only fields with an
+// FK target.
+const fkSemanticType = "type/FK"
+
+func NewFieldMetadata() resource.Resource {
+	fieldMetadata := &FieldMetadata{}
+
+	baseResource := &BaseResource{
+		TypeName: "field_metadata",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			fieldMetadata.repository = repositories.NewFieldRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Curated metadata of a column discovered by Metabase's schema sync (the data model editor): display name, description, semantic type, visibility and foreign key target. The field is adopted, never created or deleted: it must have been synced, and removing the resource leaves its metadata as it is. Attributes left unset keep the value Metabase has.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Field ID",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"table_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the table holding the field",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the column in the database",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"display_name": schema.StringAttribute{
+						MarkdownDescription: "Name of the field shown in Metabase",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"description": schema.StringAttribute{
+						MarkdownDescription: "Description of the field; \"\" for none",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"semantic_type": schema.StringAttribute{
+						MarkdownDescription: "What the values mean, e.g. \"type/PK\", \"type/FK\", \"type/Email\", \"type/Category\" or \"type/Currency\"; \"\" for none",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"visibility_type": schema.StringAttribute{
+						MarkdownDescription: "Where the field is shown: \"normal\" (everywhere), \"details-only\" (only in the detail view), \"sensitive\" (never shown, can't be filtered on), \"hidden\" or \"retired\".",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+						Validators:          []validator.String{OneOfValidator("normal", "details-only", "sensitive", "hidden", "retired")},
+					},
+					"fk_target_field_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the field this foreign key points to; \"\" for none. Only for `semantic_type = \"type/FK\"`: Metabase clears it for other semantic types.",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.FieldMetadataTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			found, err := fieldMetadata.repository.FindByName(ctx, plan.TableId.ValueString(), plan.Name.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to look up field: %s", err))
+				return
+			}
+			if found == nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("No field named %q in table %s. Fields are only created by Metabase's schema sync: check the name or wait for the sync to finish.", plan.Name.ValueString(), plan.TableId.ValueString()))
+				return
+			}
+
+			updated, err := fieldMetadata.repository.UpdateMetadata(ctx, strconv.Itoa(found.Id), fieldMetadataInput(plan))
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to update field metadata: %s", err))
+				return
+			}
+
+			result := terraform.CreateFieldMetadataTerraformModelFromDTO(updated)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.FieldMetadataTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			field, err := fieldMetadata.repository.Get(ctx, state.Id.ValueString())
+			if err != nil {
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.State.RemoveResource(ctx)
+					return
+				}
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get field: %s", err))
+				return
+			}
+			// Dropped from the database: Metabase keeps it inactive.
+			if !field.Active {
+				resp.State.RemoveResource(ctx)
+				return
+			}
+
+			result := terraform.CreateFieldMetadataTerraformModelFromDTO(field)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.FieldMetadataTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			updated, err := fieldMetadata.repository.UpdateMetadata(ctx, plan.Id.ValueString(), fieldMetadataInput(plan))
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update field metadata: %s", err))
+				return
+			}
+
+			result := terraform.CreateFieldMetadataTerraformModelFromDTO(updated)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			// Adopt-only: the field and its metadata are left as they are.
+		},
+	}
+
+	fieldMetadata.BaseResource = baseResource
+
+	return fieldMetadata
+}
+
+// fieldMetadataInput returns the metadata to write; unset attributes are omitted.
+func fieldMetadataInput(plan terraform.FieldMetadataTerraformModel) dtos.FieldMetadataDTO {
+	return dtos.FieldMetadataDTO{
+		DisplayName:     knownStringPointer(plan.DisplayName),
+		Description:     knownStringPointer(plan.Description),
+		SemanticType:    knownStringPointer(plan.SemanticType),
+		VisibilityType:  knownStringPointer(plan.VisibilityType),
+		FkTargetFieldId: knownStringPointer(plan.FkTargetFieldId),
+	}
+}
+
+var _ resource.ResourceWithValidateConfig = &FieldMetadata{}
+
+// ValidateConfig implements resource.ResourceWithValidateConfig.
+func (f *FieldMetadata) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config terraform.FieldMetadataTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.FkTargetFieldId.ValueString() != "" && !config.SemanticType.IsNull() && !config.SemanticType.IsUnknown() && config.SemanticType.ValueString() != fkSemanticType {
+		resp.Diagnostics.AddAttributeError(path.Root("fk_target_field_id"), "Invalid Attribute Combination", fmt.Sprintf("`fk_target_field_id` requires `semantic_type = %q`, got %q.", fkSemanticType, config.SemanticType.ValueString()))
+	}
+}
+
+var _ resource.ResourceWithModifyPlan = &FieldMetadata{}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. Metabase drops the FK
+// target of a field that stops being a foreign key: plan that instead of
+// carrying the old target over.
+func (f *FieldMetadata) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return // destroy
+	}
+	var config, plan terraform.FieldMetadataTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.FkTargetFieldId.IsNull() && !plan.SemanticType.IsUnknown() && !plan.SemanticType.IsNull() && plan.SemanticType.ValueString() != fkSemanticType {
+		plan.FkTargetFieldId = types.StringValue("")
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	}
+}
+
+// FieldMetadata defines the resource implementation.
+type FieldMetadata struct {
+	*BaseResource
+	repository *repositories.FieldRepository
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccFieldMetadataResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSampleDatabase(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFieldMetadataConfig(`
+  semantic_type      = "type/Category"
+  fk_target_field_id = "1"`),
+				ExpectError: regexp.MustCompile("`fk_target_field_id` requires"),
+			},
+			{
+				Config: testAccFieldMetadataConfig(`
+  display_name       = "Buyer"
+  description        = "Who placed the order"
+  semantic_type      = "type/FK"
+  visibility_type    = "normal"
+  fk_target_field_id = metabase_field_metadata.people_id.id`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_field_metadata.test", "display_name", "Buyer"),
+					resource.TestCheckResourceAttr("metabase_field_metadata.test", "description", "Who placed the order"),
+					resource.TestCheckResourceAttr("metabase_field_metadata.test", "semantic_type", "type/FK"),
+					resource.TestCheckResourceAttrPair("metabase_field_metadata.test", "fk_target_field_id", "metabase_field_metadata.people_id", "id"),
+				),
+			},
+			{
+				ResourceName:      "metabase_field_metadata.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropping the FK semantic type clears the target (planned, not drift).
+			{
+				Config: testAccFieldMetadataConfig(`
+  display_name    = "User ID"
+  description     = ""
+  semantic_type   = "type/Category"
+  visibility_type = "details-only"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_field_metadata.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_field_metadata.test", "visibility_type", "details-only"),
+					resource.TestCheckResourceAttr("metabase_field_metadata.test", "fk_target_field_id", ""),
+				),
+			},
+			// Restore the Sample Database's foreign key.
+			{
+				Config: testAccFieldMetadataConfig(`
+  semantic_type      = "type/FK"
+  visibility_type    = "normal"
+  fk_target_field_id = metabase_field_metadata.people_id.id`),
+			},
+		},
+	})
+}
+
+func testAccFieldMetadataConfig(attributes string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+data "metabase_database" "sample" {
+  name = "Sample Database"
+}
+
+data "metabase_table" "orders" {
+  database_id = data.metabase_database.sample.id
+  schema      = "PUBLIC"
+  name        = "ORDERS"
+}
+
+data "metabase_table" "people" {
+  database_id = data.metabase_database.sample.id
+  schema      = "PUBLIC"
+  name        = "PEOPLE"
+}
+
+# Adopts PEOPLE.ID unchanged, to reference its field id.
+resource "metabase_field_metadata" "people_id" {
+  table_id = data.metabase_table.people.id
+  name     = "ID"
+}
+
+resource "metabase_field_metadata" "test" {
+  table_id = data.metabase_table.orders.id
+  name     = "USER_ID"
+%s
+}
+`, attributes)
+}
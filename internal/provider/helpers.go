@@ -3,7 +3,11 @@
 
 package provider
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 func idOf(left, right string) types.String {
 	return types.StringValue(left + ":" + right)
@@ -12,3 +16,34 @@ func idOf(left, right string) types.String {
 func stringValue(s string) types.String {
 	return types.StringValue(s)
 }
+
+func stringPtr(s string) *string { return &s }
+
+// knownStringPointer returns nil for a null or unknown value.
+func knownStringPointer(v types.String) *string {
+	if v.IsUnknown() {
+		return nil
+	}
+	return v.ValueStringPointer()
+}
+
+// knownBoolPointer returns nil for a null or unknown value.
+func knownBoolPointer(v types.Bool) *bool {
+	if v.IsUnknown() {
+		return nil
+	}
+	return v.ValueBoolPointer()
+}
+
+// optionalRegex compiles a name_regex-style filter; a null pattern yields nil,
+// which matchesRegex treats as matching everything.
+func optionalRegex(pattern types.String) (*regexp.Regexp, error) {
+	if pattern.IsNull() {
+		return nil, nil
+	}
+	return regexp.Compile(pattern.ValueString())
+}
+
+func matchesRegex(re *regexp.Regexp, s string) bool {
+	return re == nil || re.MatchString(s)
+}
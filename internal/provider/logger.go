@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tflogLogger routes the SDK client's request logs to tflog, so they follow
+// TF_LOG / TF_LOG_PROVIDER (bodies only at TRACE).
+type tflogLogger struct{}
+
+var _ metabase.Logger = tflogLogger{}
+var _ metabase.TraceEnabler = tflogLogger{}
+
+// traceLevelEnv are the variables setting the provider's log level, most
+// specific first; the first one set decides.
+var traceLevelEnv = []string{"TF_LOG_PROVIDER_METABASE", "TF_LOG_PROVIDER", "TF_ACC_LOG", "TF_LOG"}
+
+func (tflogLogger) Debug(ctx context.Context, msg string, fields map[string]any) {
+	tflog.Debug(ctx, msg, fields)
+}
+
+func (tflogLogger) Trace(ctx context.Context, msg string, fields map[string]any) {
+	tflog.Trace(ctx, msg, fields)
+}
+
+// TraceEnabled reports whether TRACE entries are emitted ("JSON" logs at
+// TRACE too), so that response bodies are only buffered then.
+func (tflogLogger) TraceEnabled(context.Context) bool {
+	for _, name := range traceLevelEnv {
+		if level := os.Getenv(name); level != "" {
+			return strings.EqualFold(level, "trace") || strings.EqualFold(level, "json")
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+func NewPermissionGroupDataSource() datasource.DataSource {
+	permissionGroup := &PermissionGroupDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "permission_group",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			permissionGroup.repository = repositories.NewPermissionGroupRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Looks up an existing permission group by id or by name, e.g. the built-in \"All Users\" and \"Administrators\" groups.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						MarkdownDescription: "Permission group ID. Set exactly one of id or name.",
+						Optional:            true,
+						Computed:            true,
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Exact name of the permission group. Set exactly one of id or name.",
+						Optional:            true,
+						Computed:            true,
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.PermissionGroupTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if config.Id.IsNull() == config.Name.IsNull() {
+				resp.Diagnostics.AddError("Invalid lookup", "Set exactly one of id or name.")
+				return
+			}
+
+			var group *dtos.PermissionGroupDTO
+			var err error
+			if !config.Id.IsNull() {
+				group, err = permissionGroup.repository.Get(ctx, config.Id.ValueString())
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No permission group with id %s", config.Id.ValueString()))
+					return
+				}
+			} else {
+				group, err = permissionGroup.repository.FindByName(ctx, config.Name.ValueString())
+				if err == nil && group == nil {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No permission group named %q", config.Name.ValueString()))
+					return
+				}
+			}
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get permission group: %s", err))
+				return
+			}
+
+			result := terraform.CreatePermissionGroupTerraformModelFromDTO(group)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+	}
+
+	permissionGroup.BaseDataSource = baseDataSource
+
+	return permissionGroup
+}
+
+// PermissionGroupDataSource defines the data source implementation.
+type PermissionGroupDataSource struct {
+	*BaseDataSource
+	repository *repositories.PermissionGroupRepository
+}
@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccPermissionGroupDataSource looks up the built-in groups by name.
+func TestAccPermissionGroupDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+data "metabase_permission_group" "all_users" {
+  name = "All Users"
+}
+
+data "metabase_permission_group" "administrators" {
+  name = "Administrators"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.metabase_permission_group.all_users", "id", "1"),
+					resource.TestCheckResourceAttr("data.metabase_permission_group.administrators", "id", "2"),
+				),
+			},
+			{
+				Config: testAccProviderConfig() + `
+data "metabase_permission_group" "missing" {
+  name = "No such group"
+}
+`,
+				ExpectError: regexp.MustCompile("No permission group named"),
+			},
+		},
+	})
+}
@@ -0,0 +1,301 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewPermissionGroupMembers() resource.Resource {
+	groupMembers := &PermissionGroupMembers{}
+
+	baseResource := &BaseResource{
+		TypeName: "permission_group_members",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			groupMembers.repository = repositories.NewUserPermissionGroupMembershipRepository(client)
+			groupMembers.groupRepository = repositories.NewPermissionGroupRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Every member of one permission group, managed authoritatively: on apply, users not declared here are removed from the group, and users added outside Terraform show up as drift. Use it instead of (not together with) `metabase_user_permission_group_membership` for the same group. Removing the resource removes all of the group's members.\n\n" +
+					"Planning fails for the All Users group, whose membership can't be changed, and the Administrators group, whose members are the admins (see `is_superuser` on `metabase_user`).",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "ID of the permission group (same as group_id)",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"group_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the permission group",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"members": schema.SetAttribute{
+						MarkdownDescription: "IDs of the users in the group. Users not listed are removed; an empty set empties the group.",
+						ElementType:         types.StringType,
+						Required:            true,
+					},
+					"group_managers": schema.SetAttribute{
+						MarkdownDescription: "Enterprise. IDs of the members who manage the group (can add and remove its members); must be a subset of `members`. Members not listed are demoted. Unset to leave group managers unmanaged. Requires the `advanced_permissions` feature; planning fails on instances without it.",
+						ElementType:         types.StringType,
+						Optional:            true,
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.PermissionGroupMembersTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			result := groupMembers.replace(ctx, plan, "Create Error", &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.PermissionGroupMembersTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// On import only the id is set.
+			groupId := state.Id.ValueString()
+
+			_, err := groupMembers.groupRepository.Get(ctx, groupId)
+			if err != nil {
+				// Group deleted out-of-band (404): its memberships went with it.
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.State.RemoveResource(ctx)
+					return
+				}
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get permission group: %s", err))
+				return
+			}
+
+			memberships, err := groupMembers.repository.ListGroup(ctx, groupId)
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to list group members: %s", err))
+				return
+			}
+
+			result := terraform.CreatePermissionGroupMembersTerraformModelFromDTO(groupId, memberships, state)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.PermissionGroupMembersTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			result := groupMembers.replace(ctx, plan, "Update Error", &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			var state terraform.PermissionGroupMembersTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// Remove everyone: replace with no members.
+			emptied := terraform.PermissionGroupMembersTerraformModel{
+				GroupId:       state.GroupId,
+				Members:       types.SetValueMust(types.StringType, nil),
+				GroupManagers: types.SetNull(types.StringType),
+			}
+			groupMembers.replace(ctx, emptied, "Delete Error", &resp.Diagnostics)
+		},
+	}
+
+	groupMembers.BaseResource = baseResource
+
+	return groupMembers
+}
+
+// replace makes the plan's members the group's complete set: the delta is
+// computed from a single membership listing, and the memberships are listed
+// again afterwards for the resulting state.
+func (g *PermissionGroupMembers) replace(ctx context.Context, plan terraform.PermissionGroupMembersTerraformModel, summary string, diags *diag.Diagnostics) terraform.PermissionGroupMembersTerraformModel {
+	var members, managers []string
+	diags.Append(plan.Members.ElementsAs(ctx, &members, false)...)
+	manageManagers := !plan.GroupManagers.IsNull()
+	if manageManagers {
+		diags.Append(plan.GroupManagers.ElementsAs(ctx, &managers, false)...)
+	}
+	if diags.HasError() {
+		return plan
+	}
+
+	groupId := plan.GroupId.ValueString()
+	current, err := g.repository.ListGroup(ctx, groupId)
+	if err != nil {
+		diags.AddError(summary, fmt.Sprintf("Unable to list group members: %s", err))
+		return plan
+	}
+
+	isManager := map[string]bool{}
+	for _, userId := range managers {
+		isManager[userId] = true
+	}
+	declared := map[string]bool{}
+	for _, userId := range members {
+		declared[userId] = true
+	}
+
+	existing := map[string]bool{}
+	for _, m := range current {
+		userId := strconv.Itoa(m.UserId)
+		existing[userId] = true
+		switch {
+		case !declared[userId]:
+			err = g.repository.Delete(ctx, strconv.Itoa(m.MembershipId))
+		case manageManagers && m.IsGroupManager != isManager[userId]:
+			err = g.repository.SetGroupManager(ctx, m.MembershipId, isManager[userId])
+		}
+		if err != nil {
+			diags.AddError(summary, fmt.Sprintf("Unable to update the membership of user %s: %s", userId, err))
+			return plan
+		}
+	}
+	for _, userId := range members {
+		if existing[userId] {
+			continue
+		}
+		var manager *bool
+		if manageManagers {
+			v := isManager[userId]
+			manager = &v
+		}
+		if err := g.repository.Add(ctx, userId, groupId, manager); err != nil {
+			diags.AddError(summary, fmt.Sprintf("Unable to add user %s to the group: %s", userId, err))
+			return plan
+		}
+	}
+
+	updated, err := g.repository.ListGroup(ctx, groupId)
+	if err != nil {
+		diags.AddError(summary, fmt.Sprintf("Unable to list group members: %s", err))
+		return plan
+	}
+	return terraform.CreatePermissionGroupMembersTerraformModelFromDTO(groupId, updated, plan)
+}
+
+var _ resource.ResourceWithValidateConfig = &PermissionGroupMembers{}
+
+// ValidateConfig implements resource.ResourceWithValidateConfig.
+func (g *PermissionGroupMembers) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config terraform.PermissionGroupMembersTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Members.IsUnknown() || config.GroupManagers.IsNull() || config.GroupManagers.IsUnknown() {
+		return
+	}
+
+	for _, manager := range config.GroupManagers.Elements() {
+		if manager.IsUnknown() {
+			continue
+		}
+		found := false
+		for _, member := range config.Members.Elements() {
+			if member.IsUnknown() {
+				return // can't tell yet
+			}
+			found = found || member.Equal(manager)
+		}
+		if !found {
+			resp.Diagnostics.AddAttributeError(path.Root("group_managers"), "Invalid Attribute Combination", fmt.Sprintf("Group manager %s is not in `members`: group managers must be members of the group.", manager))
+		}
+	}
+}
+
+var _ resource.ResourceWithModifyPlan = &PermissionGroupMembers{}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. It fails the plan
+// for the built-in All Users and Administrators groups, and when group
+// managers are set on an instance without them (OSS, or a license without
+// advanced permissions).
+func (g *PermissionGroupMembers) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || g.repository == nil {
+		return // destroy, or the provider is not configured yet
+	}
+	var plan terraform.PermissionGroupMembersTerraformModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	g.checkBuiltInGroup(ctx, plan, &resp.Diagnostics)
+
+	if plan.GroupManagers.IsNull() {
+		return
+	}
+	if err := g.repository.SupportsGroupManagers(); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("group_managers"), "Unsupported Feature", fmt.Sprintf("%s. Group managers are a Metabase Enterprise feature.", err))
+	}
+}
+
+// builtInGroups are Metabase's magic groups by id: All Users membership can't
+// be changed, and Administrators membership is whether a user is an admin.
+var builtInGroups = map[string]string{
+	"1": "All Users",
+	"2": "Administrators",
+}
+
+// checkBuiltInGroup fails the plan when group_id is one of the built-in
+// groups, matched by id or, once the group can be read, by name.
+func (g *PermissionGroupMembers) checkBuiltInGroup(ctx context.Context, plan terraform.PermissionGroupMembersTerraformModel, diags *diag.Diagnostics) {
+	if plan.GroupId.IsUnknown() || plan.GroupId.IsNull() {
+		return // a group created in the same apply is never built-in
+	}
+	groupId := plan.GroupId.ValueString()
+	name, builtIn := builtInGroups[groupId]
+	if !builtIn {
+		group, err := g.groupRepository.Get(ctx, groupId)
+		if err != nil {
+			return // surfaced on apply
+		}
+		for _, builtInName := range builtInGroups {
+			builtIn = builtIn || group.Name == builtInName
+		}
+		name = group.Name
+	}
+	if builtIn {
+		diags.AddAttributeError(path.Root("group_id"), "Invalid Attribute Value", fmt.Sprintf("Group %s is the built-in %q group, whose members can't be managed by this resource. For admins, set `is_superuser` on `metabase_user` instead.", groupId, name))
+	}
+}
+
+// PermissionGroupMembers defines the resource implementation.
+type PermissionGroupMembers struct {
+	*BaseResource
+	repository      *repositories.UserPermissionGroupMembershipRepository
+	groupRepository *repositories.PermissionGroupRepository
+}
@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccCheckGroupMembersRemoved asserts that after destroy the group has no
+// member left.
+func testAccCheckGroupMembersRemoved(s *terraform.State) error {
+	repo := repositories.NewUserPermissionGroupMembershipRepository(newTestMetabaseClient())
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metabase_permission_group_members" {
+			continue
+		}
+		memberships, err := repo.ListGroup(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if len(memberships) > 0 {
+			return fmt.Errorf("group %s still has members after destroy: %v", rs.Primary.ID, memberships)
+		}
+	}
+	return nil
+}
+
+// TestAccPermissionGroupMembersResource covers create, import, that a member
+// added outside Terraform shows up as drift and is removed on apply, and
+// removing a declared member.
+func TestAccPermissionGroupMembersResource(t *testing.T) {
+	suffix := rand.Int()
+	var groupId, otherUserId string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGroupMembersRemoved,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPermissionGroupMembersConfig(suffix, "[metabase_user.a.id, metabase_user.b.id]", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("metabase_permission_group_members.test", "id", "metabase_permission_group.test", "id"),
+					resource.TestCheckResourceAttr("metabase_permission_group_members.test", "members.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair("metabase_permission_group_members.test", "members.*", "metabase_user.a", "id"),
+					resource.TestCheckTypeSetElemAttrPair("metabase_permission_group_members.test", "members.*", "metabase_user.b", "id"),
+					resource.TestCheckNoResourceAttr("metabase_permission_group_members.test", "group_managers"),
+					func(s *terraform.State) error {
+						groupId = s.RootModule().Resources["metabase_permission_group.test"].Primary.ID
+						otherUserId = s.RootModule().Resources["metabase_user.other"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "metabase_permission_group_members.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Add the undeclared user out-of-band: drift, removed on apply.
+			{
+				PreConfig: func() {
+					repo := repositories.NewUserPermissionGroupMembershipRepository(newTestMetabaseClient())
+					if err := repo.Add(context.Background(), otherUserId, groupId, nil); err != nil {
+						t.Fatalf("out-of-band membership failed: %s", err)
+					}
+				},
+				Config: testAccPermissionGroupMembersConfig(suffix, "[metabase_user.a.id, metabase_user.b.id]", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_permission_group_members.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_permission_group_members.test", "members.#", "2"),
+					func(s *terraform.State) error {
+						memberships, err := repositories.NewUserPermissionGroupMembershipRepository(newTestMetabaseClient()).ListGroup(context.Background(), groupId)
+						if err != nil {
+							return err
+						}
+						for _, m := range memberships {
+							if fmt.Sprint(m.UserId) == otherUserId {
+								return fmt.Errorf("out-of-band member %s was not removed", otherUserId)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccPermissionGroupMembersConfig(suffix, "[metabase_user.a.id]", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_permission_group_members.test", "members.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("metabase_permission_group_members.test", "members.*", "metabase_user.a", "id"),
+				),
+			},
+			{
+				Config:      testAccPermissionGroupMembersConfig(suffix, "[metabase_user.a.id]", `group_managers = ["0"]`),
+				ExpectError: regexp.MustCompile("group managers must be members of the group"),
+			},
+		},
+	})
+}
+
+func TestAccPermissionGroupMembersResource_GroupManagers(t *testing.T) {
+	suffix := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckTokenFeature(t, "advanced_permissions")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGroupMembersRemoved,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPermissionGroupMembersConfig(suffix, "[metabase_user.a.id, metabase_user.b.id]", "group_managers = [metabase_user.a.id]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_permission_group_members.test", "group_managers.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("metabase_permission_group_members.test", "group_managers.*", "metabase_user.a", "id"),
+				),
+			},
+			// Managers are swapped in place.
+			{
+				Config: testAccPermissionGroupMembersConfig(suffix, "[metabase_user.a.id, metabase_user.b.id]", "group_managers = [metabase_user.b.id]"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_permission_group_members.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_permission_group_members.test", "group_managers.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("metabase_permission_group_members.test", "group_managers.*", "metabase_user.b", "id"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccPermissionGroupMembersResource_BuiltInGroups checks that the All
+// Users and Administrators groups are refused at plan time.
+func TestAccPermissionGroupMembersResource_BuiltInGroups(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPermissionGroupMembersBuiltInConfig("All Users"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("built-in"),
+			},
+			{
+				Config:      testAccPermissionGroupMembersBuiltInConfig("Administrators"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("built-in"),
+			},
+		},
+	})
+}
+
+func testAccPermissionGroupMembersBuiltInConfig(groupName string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+data "metabase_permission_group" "built_in" {
+  name = %[1]q
+}
+
+resource "metabase_permission_group_members" "test" {
+  group_id = data.metabase_permission_group.built_in.id
+  members  = []
+}
+`, groupName)
+}
+
+func testAccPermissionGroupMembersConfig(suffix int, members, managers string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_user" "a" {
+  email      = "members-a-%[1]d@test.com"
+  first_name = "Members"
+  last_name  = "A"
+}
+
+resource "metabase_user" "b" {
+  email      = "members-b-%[1]d@test.com"
+  first_name = "Members"
+  last_name  = "B"
+}
+
+resource "metabase_user" "other" {
+  email      = "members-other-%[1]d@test.com"
+  first_name = "Members"
+  last_name  = "Other"
+}
+
+resource "metabase_permission_group" "test" {
+  name = "Test members group %[1]d"
+}
+
+resource "metabase_permission_group_members" "test" {
+  group_id = metabase_permission_group.test.id
+  members  = %[2]s
+  %[3]s
+}
+`, suffix, members, managers)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func NewPermissionGroupsDataSource() datasource.DataSource {
+	permissionGroups := &PermissionGroupsDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "permission_groups",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			permissionGroups.repository = repositories.NewPermissionGroupRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Lists permission groups, built-in ones included, optionally filtered by name.",
+				Attributes: map[string]schema.Attribute{
+					"name_regex": schema.StringAttribute{
+						MarkdownDescription: "Only list groups whose name matches this regular expression.",
+						Optional:            true,
+						Validators:          []validator.String{RegexValidator()},
+					},
+					"groups": schema.ListNestedAttribute{
+						MarkdownDescription: "Matching permission groups, ordered by ID",
+						Computed:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									MarkdownDescription: "Permission group ID",
+									Computed:            true,
+								},
+								"name": schema.StringAttribute{
+									MarkdownDescription: "Name of the permission group",
+									Computed:            true,
+								},
+							},
+						},
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.PermissionGroupsDataSourceTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			nameRegex, err := optionalRegex(config.NameRegex)
+			if err != nil {
+				resp.Diagnostics.AddError("Invalid name_regex", err.Error())
+				return
+			}
+
+			list, err := permissionGroups.repository.List(ctx)
+			if err != nil {
+				resp.Diagnostics.AddError("List Error", fmt.Sprintf("Unable to list permission groups: %s", err))
+				return
+			}
+			sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+
+			config.Groups = []terraform.PermissionGroupTerraformModel{}
+			for i := range list {
+				if matchesRegex(nameRegex, list[i].Name) {
+					config.Groups = append(config.Groups, terraform.CreatePermissionGroupTerraformModelFromDTO(&list[i]))
+				}
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+		},
+	}
+
+	permissionGroups.BaseDataSource = baseDataSource
+
+	return permissionGroups
+}
+
+// PermissionGroupsDataSource defines the data source implementation.
+type PermissionGroupsDataSource struct {
+	*BaseDataSource
+	repository *repositories.PermissionGroupRepository
+}
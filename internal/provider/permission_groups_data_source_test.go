@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccPermissionGroupsDataSource lists groups filtered by name.
+func TestAccPermissionGroupsDataSource(t *testing.T) {
+	prefix := fmt.Sprintf("Test groups ds %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_permission_group" "a" {
+  name = "%[1]s a"
+}
+
+resource "metabase_permission_group" "b" {
+  name = "%[1]s b"
+}
+
+data "metabase_permission_groups" "test" {
+  name_regex = "^%[1]s "
+  depends_on = [metabase_permission_group.a, metabase_permission_group.b]
+}
+
+data "metabase_permission_groups" "all" {}
+`, prefix),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.metabase_permission_groups.test", "groups.#", "2"),
+					resource.TestCheckResourceAttrPair("data.metabase_permission_groups.test", "groups.0.id", "metabase_permission_group.a", "id"),
+					resource.TestCheckResourceAttr("data.metabase_permission_groups.all", "groups.0.name", "All Users"),
+				),
+			},
+		},
+	})
+}
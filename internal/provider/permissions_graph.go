@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewPermissionsGraph() resource.Resource {
+	permissionsGraph := &PermissionsGraph{}
+
+	baseResource := &BaseResource{
+		TypeName: "permissions_graph",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			permissionsGraph.databaseRepository = repositories.NewDatabasePermissionRepository(client)
+			permissionsGraph.collectionRepository = repositories.NewCollectionPermissionRepository(client)
+			permissionsGraph.groupRepository = repositories.NewPermissionGroupRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Every data and collection permission of one permission group, managed authoritatively: on apply, any database or collection grant not declared here is revoked, and grants made outside Terraform show up as drift. Use it instead of (not together with) `metabase_database_permission` and `metabase_collection_permission` for the same group. Removing the resource revokes all of the group's grants.\n\n" +
+					"Data grants are the query-building level (`create-queries`); view-data is not managed. Personal collections and the Trash are never touched. Not for the Administrators group, whose access can't be changed. Requires Metabase 50 or later.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "ID of the permission group (same as group_id)",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"group_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the permission group",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"databases": schema.MapAttribute{
+						MarkdownDescription: "Query-building access per database, keyed by database ID: \"query-builder\" or \"query-builder-and-native\". Databases not listed are revoked (\"no\").",
+						ElementType:         types.StringType,
+						Optional:            true,
+						Validators:          []validator.Map{MapValuesOneOfValidator("query-builder", "query-builder-and-native")},
+					},
+					"collections": schema.MapAttribute{
+						MarkdownDescription: "Access per collection, keyed by collection ID (or `\"root\"` for \"Our Analytics\"): \"read\" or \"write\". Collections not listed are revoked (\"none\"). Permissions are not propagated to sub-collections: list each one.",
+						ElementType:         types.StringType,
+						Optional:            true,
+						Validators:          []validator.Map{MapValuesOneOfValidator("read", "write")},
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.PermissionsGraphTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			permissionsGraph.replace(ctx, plan, "Create Error", &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			plan.Id = plan.GroupId
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.PermissionsGraphTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// On import only the id is set.
+			groupId := state.Id.ValueString()
+
+			_, err := permissionsGraph.groupRepository.Get(ctx, groupId)
+			if err != nil {
+				// Group deleted out-of-band (404): its grants went with it.
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.State.RemoveResource(ctx)
+					return
+				}
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get permission group: %s", err))
+				return
+			}
+
+			databases, err := permissionsGraph.databaseRepository.GetGroup(ctx, groupId)
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get data permissions: %s", err))
+				return
+			}
+			collections, err := permissionsGraph.collectionRepository.GetGroup(ctx, groupId)
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get collection permissions: %s", err))
+				return
+			}
+
+			result := terraform.CreatePermissionsGraphTerraformModel(groupId, databases, collections, state)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.PermissionsGraphTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			permissionsGraph.replace(ctx, plan, "Update Error", &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			var state terraform.PermissionsGraphTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// Revoke everything: replace with no grants.
+			revoked := terraform.PermissionsGraphTerraformModel{
+				GroupId:     state.GroupId,
+				Databases:   types.MapNull(types.StringType),
+				Collections: types.MapNull(types.StringType),
+			}
+			permissionsGraph.replace(ctx, revoked, "Delete Error", &resp.Diagnostics)
+		},
+	}
+
+	permissionsGraph.BaseResource = baseResource
+
+	return permissionsGraph
+}
+
+// replace makes the plan's grants the group's complete set, data graph first.
+func (p *PermissionsGraph) replace(ctx context.Context, plan terraform.PermissionsGraphTerraformModel, summary string, diags *diag.Diagnostics) {
+	databases := map[string]string{}
+	collections := map[string]string{}
+	diags.Append(plan.Databases.ElementsAs(ctx, &databases, false)...)
+	diags.Append(plan.Collections.ElementsAs(ctx, &collections, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	groupId := plan.GroupId.ValueString()
+	if err := p.databaseRepository.ReplaceGroup(ctx, groupId, databases); err != nil {
+		diags.AddError(summary, fmt.Sprintf("Unable to set data permissions: %s", err))
+		return
+	}
+	if err := p.collectionRepository.ReplaceGroup(ctx, groupId, collections); err != nil {
+		diags.AddError(summary, fmt.Sprintf("Unable to set collection permissions: %s", err))
+		return
+	}
+}
+
+// PermissionsGraph defines the resource implementation.
+type PermissionsGraph struct {
+	*BaseResource
+	databaseRepository   *repositories.DatabasePermissionRepository
+	collectionRepository *repositories.CollectionPermissionRepository
+	groupRepository      *repositories.PermissionGroupRepository
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccCheckPermissionsGraphRevoked asserts that after destroy the group has
+// no data or collection grant left.
+func testAccCheckPermissionsGraphRevoked(s *terraform.State) error {
+	client := newTestMetabaseClient()
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metabase_permissions_graph" {
+			continue
+		}
+		databases, err := repositories.NewDatabasePermissionRepository(client).GetGroup(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		collections, err := repositories.NewCollectionPermissionRepository(client).GetGroup(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if len(databases) > 0 || len(collections) > 0 {
+			return fmt.Errorf("group %s still has grants after destroy: %v %v", rs.Primary.ID, databases, collections)
+		}
+	}
+	return nil
+}
+
+// TestAccPermissionsGraphResource covers create, import, and that a grant made
+// outside Terraform shows up as drift and is revoked on apply.
+func TestAccPermissionsGraphResource(t *testing.T) {
+	suffix := rand.Int()
+	var groupId, otherCollectionId string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckPermissionsGraphRevoked,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPermissionsGraphConfig(suffix),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("metabase_permissions_graph.test", "id", "metabase_permission_group.test", "id"),
+					resource.TestCheckResourceAttr("metabase_permissions_graph.test", "databases.%", "1"),
+					resource.TestCheckResourceAttr("metabase_permissions_graph.test", "collections.%", "1"),
+					func(s *terraform.State) error {
+						groupId = s.RootModule().Resources["metabase_permission_group.test"].Primary.ID
+						otherCollectionId = s.RootModule().Resources["metabase_collection.other"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "metabase_permissions_graph.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Grant access to the undeclared collection out-of-band: drift, revoked on apply.
+			{
+				PreConfig: func() {
+					repo := repositories.NewCollectionPermissionRepository(newTestMetabaseClient())
+					if err := repo.Set(context.Background(), groupId, otherCollectionId, "write"); err != nil {
+						t.Fatalf("out-of-band grant failed: %s", err)
+					}
+				},
+				Config: testAccPermissionsGraphConfig(suffix),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_permissions_graph.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(s *terraform.State) error {
+					repo := repositories.NewCollectionPermissionRepository(newTestMetabaseClient())
+					_, found, err := repo.Get(context.Background(), groupId, otherCollectionId)
+					if err != nil {
+						return err
+					}
+					if found {
+						return fmt.Errorf("out-of-band grant on collection %s was not revoked", otherCollectionId)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccPermissionsGraphConfig(suffix int) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_permission_group" "test" {
+  name = "Test pgraph group %[1]d"
+}
+
+resource "metabase_database" "test" {
+  name                = "Test pgraph db %[1]d"
+  engine              = "postgres"
+  deletion_protection = false
+  details = jsonencode({
+    host     = "sample-db"
+    port     = 5432
+    dbname   = "sampledb"
+    user     = "sampleuser"
+    password = "samplepass"
+    ssl      = false
+  })
+}
+
+resource "metabase_collection" "test" {
+  name = "Test pgraph collection %[1]d"
+}
+
+resource "metabase_collection" "other" {
+  name = "Test pgraph other collection %[1]d"
+}
+
+resource "metabase_permissions_graph" "test" {
+  group_id = metabase_permission_group.test.id
+
+  databases = {
+    (metabase_database.test.id) = "query-builder"
+  }
+
+  collections = {
+    (metabase_collection.test.id) = "read"
+  }
+}
+`, suffix)
+}
@@ -5,14 +5,24 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure MetabaseProvider satisfies various provider interfaces.
@@ -30,8 +40,21 @@ type MetabaseProvider struct {
 
 // MetabaseProviderModel describes the provider data model.
 type MetabaseProviderModel struct {
-	Host   types.String `tfsdk:"host"`
-	APIKey types.String `tfsdk:"api_key"`
+	Host         types.String `tfsdk:"host"`
+	APIKey       types.String `tfsdk:"api_key"`
+	Username     types.String `tfsdk:"username"`
+	Password     types.String `tfsdk:"password"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax types.String `tfsdk:"retry_wait_max"`
+
+	Timeout            types.String `tfsdk:"timeout"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCertFile     types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile      types.String `tfsdk:"client_key_file"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	Headers            types.Map    `tfsdk:"headers"`
 }
 
 func (p *MetabaseProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -43,12 +66,66 @@ func (p *MetabaseProvider) Schema(ctx context.Context, req provider.SchemaReques
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				MarkdownDescription: "Metabase API host URL",
-				Required:            true,
+				MarkdownDescription: "Metabase API host URL. Can also be set with the `METABASE_HOST` environment variable.",
+				Optional:            true,
 			},
 			"api_key": schema.StringAttribute{
-				MarkdownDescription: "Metabase API Key",
-				Required:            true,
+				MarkdownDescription: "Metabase API Key. Conflicts with `username`/`password`. Can also be set with the `METABASE_API_KEY` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Email of a Metabase user to log in as, for instances without API keys. The provider opens a session (`POST /api/session`), logs in again if it expires, and logs out when it exits. Requires `password`; conflicts with `api_key`. Can also be set with the `METABASE_USERNAME` environment variable.",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password of `username`. Can also be set with the `METABASE_PASSWORD` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of retries of a request that failed transiently (5xx, 429 or a network error). Only idempotent requests (GET/PUT/DELETE) are retried on 5xx and network errors; any request is retried on 429. Defaults to 4; set 0 to disable retries.",
+				Optional:            true,
+			},
+			"retry_wait_min": schema.StringAttribute{
+				MarkdownDescription: "Wait before the first retry, doubled on each subsequent one (with jitter), e.g. \"500ms\". Defaults to \"500ms\", or to `retry_wait_max` when that is set lower.",
+				Optional:            true,
+				Validators:          []validator.String{DurationValidator()},
+			},
+			"retry_wait_max": schema.StringAttribute{
+				MarkdownDescription: "Upper bound of the wait between retries, also applied to a server's `Retry-After`, e.g. \"30s\". Defaults to \"30s\", or to `retry_wait_min` when that is set higher.",
+				Optional:            true,
+				Validators:          []validator.String{DurationValidator()},
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout of each HTTP attempt, including reading the response, e.g. \"2m\". Defaults to \"2m\".",
+				Optional:            true,
+				Validators:          []validator.String{DurationValidator()},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM bundle of CA certificates trusted in addition to the system roots, for a Metabase behind a private CA.",
+				Optional:            true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM client certificate presented for mutual TLS. Requires `client_key_file`.",
+				Optional:            true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to the PEM private key of `client_cert_file`.",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip verification of Metabase's TLS certificate. Only for staging instances with self-signed certificates.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of an HTTP(S) proxy to reach Metabase through, e.g. \"http://proxy.internal:3128\". Defaults to the `HTTPS_PROXY`/`HTTP_PROXY` environment variables.",
+				Optional:            true,
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "Extra headers sent with every request, e.g. for an authenticating proxy in front of Metabase. They cannot override `Content-Type` or the Metabase credentials headers.",
+				ElementType:         types.StringType,
+				Optional:            true,
 			},
 		},
 	}
@@ -63,24 +140,259 @@ func (p *MetabaseProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	// Configuration values are now available.
-	// if data.Endpoint.IsNull() { /* ... */ }
+	retryPolicy, diags := retryPolicyFromConfig(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	metabaseClient := metabase.NewMetabaseAPIClient(data.Host.ValueString(), data.APIKey.ValueString())
+	settings, diags := resolveConnection(data, os.Getenv)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpClient, diags := httpClientFromConfig(data)
+	resp.Diagnostics.Append(diags...)
+	var headers map[string]string
+	resp.Diagnostics.Append(data.Headers.ElementsAs(ctx, &headers, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts := []metabase.ClientOption{
+		metabase.WithRetryPolicy(retryPolicy),
+		metabase.WithHTTPClient(httpClient),
+		metabase.WithHeaders(headers),
+		metabase.WithLogger(tflogLogger{}),
+	}
+	if settings.Username != "" {
+		opts = append(opts, metabase.WithSessionCredentials(settings.Username, settings.Password))
+	}
+
+	metabaseClient := metabase.NewMetabaseAPIClient(settings.Host, settings.APIKey, opts...)
+	if metabaseClient.Username != "" {
+		trackSessionClient(metabaseClient)
+	}
+
+	// Version and edition gate resources that need a recent or Enterprise
+	// instance. Not fatal: without them Metabase rejects unsupported calls itself.
+	if info, err := metabaseClient.FetchServerInfo(ctx); err != nil {
+		resp.Diagnostics.AddWarning(
+			"Unable to detect the Metabase version",
+			fmt.Sprintf("Reading /api/session/properties failed, so version and edition checks are skipped: %s", err),
+		)
+	} else {
+		tflog.Info(ctx, "Detected Metabase instance", map[string]any{"version": info.Tag, "edition": info.Edition()})
+	}
 
 	resp.DataSourceData = metabaseClient
 	resp.ResourceData = metabaseClient
 }
 
+// Environment variables read when the matching provider attribute is unset.
+const (
+	envHost     = "METABASE_HOST"
+	envAPIKey   = "METABASE_API_KEY"
+	envUsername = "METABASE_USERNAME"
+	envPassword = "METABASE_PASSWORD"
+)
+
+// connectionSettings is the provider configuration after environment fallbacks.
+type connectionSettings struct {
+	Host     string
+	APIKey   string
+	Username string
+	Password string
+}
+
+// resolveConnection fills unset attributes from the environment and requires
+// a host plus exactly one of api_key or username + password. Credentials are
+// taken as a group: if the provider block sets any of them, credential
+// environment variables are ignored, so a stray METABASE_API_KEY can't
+// conflict with an explicit username/password.
+func resolveConnection(data MetabaseProviderModel, getenv func(string) string) (connectionSettings, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var settings connectionSettings
+
+	attrs := []struct {
+		name  string
+		value types.String
+	}{{"host", data.Host}, {"api_key", data.APIKey}, {"username", data.Username}, {"password", data.Password}}
+	for _, attr := range attrs {
+		if attr.value.IsUnknown() {
+			diags.AddAttributeError(
+				path.Root(attr.name),
+				"Unknown provider attribute",
+				fmt.Sprintf("The provider cannot be configured because %s depends on a value that is not known until apply. Set it statically or use the matching METABASE_* environment variable.", attr.name),
+			)
+		}
+	}
+	if diags.HasError() {
+		return settings, diags
+	}
+
+	settings.Host = data.Host.ValueString()
+	if data.Host.IsNull() {
+		settings.Host = getenv(envHost)
+	}
+	if settings.Host == "" {
+		diags.AddAttributeError(
+			path.Root("host"),
+			"Missing Metabase host",
+			fmt.Sprintf("Set host in the provider block or the %s environment variable.", envHost),
+		)
+	}
+
+	source := "the provider block"
+	apiKeyName, usernameName, passwordName := "api_key", "username", "password"
+	if data.APIKey.IsNull() && data.Username.IsNull() && data.Password.IsNull() {
+		source = "the environment"
+		apiKeyName, usernameName, passwordName = envAPIKey, envUsername, envPassword
+		settings.APIKey = getenv(envAPIKey)
+		settings.Username = getenv(envUsername)
+		settings.Password = getenv(envPassword)
+	} else {
+		settings.APIKey = data.APIKey.ValueString()
+		settings.Username = data.Username.ValueString()
+		settings.Password = data.Password.ValueString()
+	}
+
+	hasAPIKey := settings.APIKey != ""
+	hasUsername := settings.Username != ""
+	hasPassword := settings.Password != ""
+	switch {
+	case hasAPIKey && (hasUsername || hasPassword):
+		diags.AddAttributeError(path.Root("api_key"), "Conflicting credentials", fmt.Sprintf("Both %s and %s/%s are set in %s; set only one of them.", apiKeyName, usernameName, passwordName, source))
+	case hasUsername && !hasPassword:
+		diags.AddAttributeError(path.Root("password"), "Missing password", fmt.Sprintf("%s is set in %s but %s is not.", usernameName, source, passwordName))
+	case hasPassword && !hasUsername:
+		diags.AddAttributeError(path.Root("username"), "Missing username", fmt.Sprintf("%s is set in %s but %s is not.", passwordName, source, usernameName))
+	case !hasAPIKey && !hasUsername:
+		diags.AddError(
+			"Missing Metabase credentials",
+			fmt.Sprintf("Set api_key, or username and password, in the provider block, or the %s (or %s and %s) environment variables.", envAPIKey, envUsername, envPassword),
+		)
+	}
+	return settings, diags
+}
+
+// Clients authenticated with username/password hold a server-side session;
+// Shutdown ends them when the plugin process exits (the framework has no
+// provider close hook).
+var (
+	sessionClientsMu sync.Mutex
+	sessionClients   []*metabase.MetabaseAPIClient
+)
+
+func trackSessionClient(client *metabase.MetabaseAPIClient) {
+	sessionClientsMu.Lock()
+	defer sessionClientsMu.Unlock()
+	sessionClients = append(sessionClients, client)
+}
+
+// Shutdown logs out every session opened by this process. Errors are ignored:
+// an orphaned session simply expires server-side.
+func Shutdown(ctx context.Context) {
+	sessionClientsMu.Lock()
+	defer sessionClientsMu.Unlock()
+	for _, client := range sessionClients {
+		_ = client.Logout(ctx)
+	}
+	sessionClients = nil
+}
+
+// retryPolicyFromConfig overlays the retry attributes that are set on the SDK defaults.
+func retryPolicyFromConfig(data MetabaseProviderModel) (metabase.RetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	policy := metabase.DefaultRetryPolicy()
+
+	if !data.MaxRetries.IsNull() {
+		if data.MaxRetries.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root("max_retries"), "Invalid max_retries", fmt.Sprintf("max_retries must be 0 or greater, got %d.", data.MaxRetries.ValueInt64()))
+		}
+		policy.MaxRetries = int(data.MaxRetries.ValueInt64())
+	}
+	if !data.RetryWaitMin.IsNull() {
+		policy.MinBackoff, _ = time.ParseDuration(data.RetryWaitMin.ValueString()) // checked by DurationValidator
+	}
+	if !data.RetryWaitMax.IsNull() {
+		policy.MaxBackoff, _ = time.ParseDuration(data.RetryWaitMax.ValueString())
+	}
+	// A bound left at its default yields to the one that is set.
+	switch {
+	case policy.MinBackoff <= policy.MaxBackoff:
+	case data.RetryWaitMin.IsNull():
+		policy.MinBackoff = policy.MaxBackoff
+	case data.RetryWaitMax.IsNull():
+		policy.MaxBackoff = policy.MinBackoff
+	default:
+		diags.AddAttributeError(path.Root("retry_wait_min"), "Invalid retry_wait_min", fmt.Sprintf("retry_wait_min (%s) must not exceed retry_wait_max (%s).", policy.MinBackoff, policy.MaxBackoff))
+	}
+	return policy, diags
+}
+
+// defaultTimeout bounds each HTTP attempt unless timeout is set.
+const defaultTimeout = 2 * time.Minute
+
+// httpClientFromConfig builds the HTTP client from the transport attributes,
+// reading the certificate files they point to.
+func httpClientFromConfig(data MetabaseProviderModel) (*http.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	cfg := metabase.TransportConfig{
+		Timeout:            defaultTimeout,
+		InsecureSkipVerify: data.InsecureSkipVerify.ValueBool(),
+		ProxyURL:           data.ProxyURL.ValueString(),
+	}
+	if !data.Timeout.IsNull() {
+		cfg.Timeout, _ = time.ParseDuration(data.Timeout.ValueString()) // checked by DurationValidator
+	}
+
+	readFile := func(attr string, value types.String) []byte {
+		if value.IsNull() {
+			return nil
+		}
+		content, err := os.ReadFile(value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root(attr), "Unable to read file", fmt.Sprintf("Unable to read %s %q: %s", attr, value.ValueString(), err))
+		}
+		return content
+	}
+	cfg.CACertPEM = readFile("ca_cert_file", data.CACertFile)
+	cfg.ClientCertPEM = readFile("client_cert_file", data.ClientCertFile)
+	cfg.ClientKeyPEM = readFile("client_key_file", data.ClientKeyFile)
+	if data.ClientCertFile.IsNull() != data.ClientKeyFile.IsNull() {
+		diags.AddAttributeError(path.Root("client_cert_file"), "Incomplete client certificate", "client_cert_file and client_key_file must be set together.")
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	client, err := metabase.NewHTTPClient(cfg)
+	if err != nil {
+		diags.AddError("Invalid transport configuration", err.Error())
+	}
+	return client, diags
+}
+
 func (p *MetabaseProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewPermissionGroup,
 		NewCollection,
 		NewUserPermissionGroupMembership,
+		NewPermissionGroupMembers,
 		NewUser,
 		NewDatabase,
 		NewDatabasePermission,
 		NewCollectionPermission,
+		NewCard,
+		NewDashboard,
+		NewPermissionsGraph,
+		NewTableMetadata,
+		NewFieldMetadata,
+		NewSandbox,
+		NewConnectionImpersonation,
+		NewApplicationPermission,
 	}
 }
 
@@ -89,7 +401,17 @@ func (p *MetabaseProvider) EphemeralResources(ctx context.Context) []func() ephe
 }
 
 func (p *MetabaseProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewPermissionGroupDataSource,
+		NewCollectionDataSource,
+		NewDatabaseDataSource,
+		NewUserDataSource,
+		NewTableDataSource,
+		NewUsersDataSource,
+		NewPermissionGroupsDataSource,
+		NewCollectionsDataSource,
+		NewDatabasesDataSource,
+	}
 }
 
 func (p *MetabaseProvider) Functions(ctx context.Context) []func() function.Function {
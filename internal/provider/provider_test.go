@@ -4,10 +4,15 @@
 package provider
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/providerserver"
-	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"context"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -25,17 +30,161 @@ func testAccPreCheck(t *testing.T) {
 	if os.Getenv("METABASE_HOST") == "" {
 		t.Fatal("METABASE_HOST must be set for acceptance tests")
 	}
-	if os.Getenv("METABASE_API_KEY") == "" {
-		t.Fatal("METABASE_API_KEY must be set for acceptance tests")
+	if os.Getenv("METABASE_API_KEY") == "" && os.Getenv("METABASE_USERNAME") == "" {
+		t.Fatal("METABASE_API_KEY (or METABASE_USERNAME and METABASE_PASSWORD) must be set for acceptance tests")
+	}
+}
+
+// testAccPreCheckTokenFeature skips the test unless the instance is an
+// Enterprise build whose license enables feature.
+func testAccPreCheckTokenFeature(t *testing.T, feature string) {
+	info, err := newTestMetabaseClient().FetchServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unable to read the Metabase version: %s", err)
+	}
+	if !info.Features[feature] {
+		t.Skipf("requires a Metabase Enterprise license with the %q feature (instance runs %s %s)", feature, info.Tag, info.Edition())
 	}
 }
 
-// for acceptance tests.
+// for acceptance tests. Connection settings come from the METABASE_*
+// environment variables, which also exercises the provider's env fallbacks.
 func testAccProviderConfig() string {
 	return `
-provider "metabase" {
-  host    = "` + os.Getenv("METABASE_HOST") + `"
-  api_key = "` + os.Getenv("METABASE_API_KEY") + `"
-}
+provider "metabase" {}
 `
 }
+
+func TestResolveConnection(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	tests := []struct {
+		name    string
+		data    MetabaseProviderModel
+		env     map[string]string
+		want    connectionSettings
+		wantErr string
+	}{
+		{
+			name: "provider block",
+			data: MetabaseProviderModel{Host: types.StringValue("http://mb"), APIKey: types.StringValue("key")},
+			want: connectionSettings{Host: "http://mb", APIKey: "key"},
+		},
+		{
+			name: "environment fallback",
+			env:  map[string]string{envHost: "http://env", envAPIKey: "env-key"},
+			want: connectionSettings{Host: "http://env", APIKey: "env-key"},
+		},
+		{
+			name: "provider block wins over environment",
+			data: MetabaseProviderModel{Host: types.StringValue("http://mb"), Username: types.StringValue("a@b.c"), Password: types.StringValue("pw")},
+			env:  map[string]string{envHost: "http://env", envAPIKey: "env-key"},
+			want: connectionSettings{Host: "http://mb", Username: "a@b.c", Password: "pw"},
+		},
+		{
+			name:    "missing host",
+			env:     map[string]string{envAPIKey: "env-key"},
+			wantErr: envHost,
+		},
+		{
+			name:    "missing credentials",
+			env:     map[string]string{envHost: "http://env"},
+			wantErr: envAPIKey,
+		},
+		{
+			name:    "conflicting environment credentials",
+			env:     map[string]string{envHost: "http://env", envAPIKey: "env-key", envUsername: "a@b.c", envPassword: "pw"},
+			wantErr: "Both METABASE_API_KEY and METABASE_USERNAME/METABASE_PASSWORD are set in the environment",
+		},
+		{
+			name:    "username without password",
+			data:    MetabaseProviderModel{Host: types.StringValue("http://mb"), Username: types.StringValue("a@b.c")},
+			env:     map[string]string{envPassword: "ignored"},
+			wantErr: "username is set in the provider block but password is not",
+		},
+		{
+			name:    "unknown host",
+			data:    MetabaseProviderModel{Host: types.StringUnknown(), APIKey: types.StringValue("key")},
+			wantErr: "host depends on a value that is not known",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Unset fields are the zero types.String, which is null.
+			got, diags := resolveConnection(tt.data, env(tt.env))
+
+			if tt.wantErr == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				if got != tt.want {
+					t.Errorf("expected %+v, got %+v", tt.want, got)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatalf("expected an error mentioning %q, got none", tt.wantErr)
+			}
+			found := false
+			for _, d := range diags.Errors() {
+				if strings.Contains(d.Detail(), tt.wantErr) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected an error mentioning %q, got %v", tt.wantErr, diags)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     MetabaseProviderModel
+		wantMin  time.Duration
+		wantMax  time.Duration
+		wantDiag bool
+	}{
+		{
+			name:    "defaults",
+			wantMin: 500 * time.Millisecond,
+			wantMax: 30 * time.Second,
+		},
+		{
+			name:    "only max, below the default min",
+			data:    MetabaseProviderModel{RetryWaitMax: types.StringValue("200ms")},
+			wantMin: 200 * time.Millisecond,
+			wantMax: 200 * time.Millisecond,
+		},
+		{
+			name:    "only min, above the default max",
+			data:    MetabaseProviderModel{RetryWaitMin: types.StringValue("1m")},
+			wantMin: time.Minute,
+			wantMax: time.Minute,
+		},
+		{
+			name:     "both set, min above max",
+			data:     MetabaseProviderModel{RetryWaitMin: types.StringValue("2s"), RetryWaitMax: types.StringValue("1s")},
+			wantDiag: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := retryPolicyFromConfig(tt.data)
+			if diags.HasError() != tt.wantDiag {
+				t.Fatalf("expected error %t, got %v", tt.wantDiag, diags)
+			}
+			if tt.wantDiag {
+				return
+			}
+			if got.MinBackoff != tt.wantMin || got.MaxBackoff != tt.wantMax {
+				t.Errorf("expected backoff %s..%s, got %s..%s", tt.wantMin, tt.wantMax, got.MinBackoff, got.MaxBackoff)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func NewSandbox() resource.Resource {
+	sandbox := &Sandbox{}
+
+	baseResource := &BaseResource{
+		TypeName: "sandbox",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			sandbox.repository = repositories.NewSandboxRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Enterprise. A data sandbox (row-level security): the rows of a table a permission group sees, filtered by a saved question and/or by user attributes. The table's view-data for the group is set to \"sandboxed\" in the same permissions graph update; on destroy it is set back to its previous level (see `previous_view_data`). The group's other tables keep their levels. Planning fails while the group has a connection impersonation on the database. Requires Metabase 50 or later with the `sandboxes` feature; planning fails on other instances.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Sandbox ID",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"group_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the sandboxed permission group",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"table_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the sandboxed table",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"card_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the saved question the group sees instead of the table; it must return a subset of the table's columns. Unset to filter the table itself.",
+						Optional:            true,
+					},
+					"attribute_remappings": schema.MapNestedAttribute{
+						MarkdownDescription: "Filters on the rows, keyed by user attribute name: each user sees the rows where the target equals their attribute's value.",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"field_id": schema.StringAttribute{
+									MarkdownDescription: "ID of the field (column of the table) filtered on. Conflicts with `template_tag`.",
+									Optional:            true,
+								},
+								"template_tag": schema.StringAttribute{
+									MarkdownDescription: "Variable of the native `card_id` question set to the attribute's value. Conflicts with `field_id`.",
+									Optional:            true,
+								},
+							},
+						},
+					},
+					"previous_view_data": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "View-data level of the table for the group before the sandbox was created, restored on destroy. \"blocked\" when it can't be restored as is: for imported sandboxes, or when the table was impersonated or already sandboxed.",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.SandboxTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			input, diags := sandboxInput(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			previous, err := sandbox.repository.TableViewData(ctx, input.GroupId, input.TableId)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to get the table's view-data: %s", err))
+				return
+			}
+			if repositories.IsPolicyViewData(previous) {
+				previous = "blocked"
+			}
+			plan.PreviousViewData = stringValue(previous)
+
+			created, err := sandbox.repository.Set(ctx, input)
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to create sandbox: %s", err))
+				return
+			}
+
+			result := terraform.CreateSandboxTerraformModelFromDTO(created, plan)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.SandboxTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			found, err := sandbox.repository.Get(ctx, state.Id.ValueString())
+			if err != nil {
+				// Also gone when the table's view-data was changed from "sandboxed".
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.State.RemoveResource(ctx)
+					return
+				}
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get sandbox: %s", err))
+				return
+			}
+
+			result := terraform.CreateSandboxTerraformModelFromDTO(found, state)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.SandboxTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			input, diags := sandboxInput(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			updated, err := sandbox.repository.Set(ctx, input)
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update sandbox: %s", err))
+				return
+			}
+
+			result := terraform.CreateSandboxTerraformModelFromDTO(updated, plan)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			var state terraform.SandboxTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			input, diags := sandboxInput(ctx, state)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			previous := state.PreviousViewData.ValueString()
+			if previous == "" {
+				previous = "blocked"
+			}
+			err := sandbox.repository.Delete(ctx, input, previous)
+			if err != nil {
+				resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("Unable to delete sandbox: %s", err))
+				return
+			}
+		},
+	}
+
+	sandbox.BaseResource = baseResource
+
+	return sandbox
+}
+
+// sandboxInput builds the sandbox to write from the plan (with its id once
+// created).
+func sandboxInput(ctx context.Context, plan terraform.SandboxTerraformModel) (dtos.SandboxDTO, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	atoi := func(attribute path.Path, v types.String) int {
+		n, err := strconv.Atoi(v.ValueString())
+		if err != nil {
+			diags.AddAttributeError(attribute, "Invalid ID", fmt.Sprintf("Expected a numeric ID, got %q.", v.ValueString()))
+		}
+		return n
+	}
+
+	input := dtos.SandboxDTO{
+		GroupId:             atoi(path.Root("group_id"), plan.GroupId),
+		TableId:             atoi(path.Root("table_id"), plan.TableId),
+		AttributeRemappings: map[string]dtos.SandboxTargetDTO{},
+	}
+	if !plan.Id.IsNull() && !plan.Id.IsUnknown() {
+		input.Id = atoi(path.Root("id"), plan.Id)
+	}
+	if !plan.CardId.IsNull() {
+		cardId := atoi(path.Root("card_id"), plan.CardId)
+		input.CardId = &cardId
+	}
+	if !plan.AttributeRemappings.IsNull() {
+		var remappings map[string]terraform.SandboxTargetTerraformModel
+		diags.Append(plan.AttributeRemappings.ElementsAs(ctx, &remappings, false)...)
+		for attribute, target := range remappings {
+			var t dtos.SandboxTargetDTO
+			if !target.FieldId.IsNull() {
+				fieldId := atoi(path.Root("attribute_remappings").AtMapKey(attribute).AtName("field_id"), target.FieldId)
+				t.FieldId = &fieldId
+			}
+			t.TemplateTag = target.TemplateTag.ValueStringPointer()
+			input.AttributeRemappings[attribute] = t
+		}
+	}
+	return input, diags
+}
+
+var _ resource.ResourceWithValidateConfig = &Sandbox{}
+
+// ValidateConfig implements resource.ResourceWithValidateConfig.
+func (s *Sandbox) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config terraform.SandboxTerraformModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.CardId.IsNull() && config.AttributeRemappings.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("attribute_remappings"), "Missing Attribute Configuration", "Set `card_id`, `attribute_remappings` or both: a sandbox without either filters nothing.")
+	}
+	if config.AttributeRemappings.IsNull() || config.AttributeRemappings.IsUnknown() {
+		return
+	}
+	for attribute, element := range config.AttributeRemappings.Elements() {
+		var target terraform.SandboxTargetTerraformModel
+		object, ok := element.(types.Object)
+		if !ok || object.IsUnknown() {
+			continue
+		}
+		resp.Diagnostics.Append(object.As(ctx, &target, basetypes.ObjectAsOptions{})...)
+		if target.FieldId.IsUnknown() || target.TemplateTag.IsUnknown() {
+			continue
+		}
+		targetPath := path.Root("attribute_remappings").AtMapKey(attribute)
+		if target.FieldId.IsNull() == target.TemplateTag.IsNull() {
+			resp.Diagnostics.AddAttributeError(targetPath, "Invalid Attribute Combination", "Set exactly one of `field_id` (a column) or `template_tag` (a variable of the native question).")
+		}
+		if !target.TemplateTag.IsNull() && config.CardId.IsNull() {
+			resp.Diagnostics.AddAttributeError(targetPath.AtName("template_tag"), "Invalid Attribute Combination", "`template_tag` requires `card_id` (a native question with that variable).")
+		}
+	}
+}
+
+var _ resource.ResourceWithModifyPlan = &Sandbox{}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan. It fails the plan on
+// instances without sandboxes (OSS, or a license without the feature), and
+// when the table's database is impersonated for the group.
+func (s *Sandbox) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || s.repository == nil {
+		return // destroy, or the provider is not configured yet
+	}
+	if err := s.repository.Supported(); err != nil {
+		resp.Diagnostics.AddError("Unsupported Feature", fmt.Sprintf("%s. Sandboxes (row-level security) are a Metabase Enterprise feature.", err))
+		return
+	}
+	if req.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	var plan terraform.SandboxTerraformModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.GroupId.IsUnknown() || plan.TableId.IsUnknown() {
+		return
+	}
+	groupId, groupErr := strconv.Atoi(plan.GroupId.ValueString())
+	tableId, tableErr := strconv.Atoi(plan.TableId.ValueString())
+	if groupErr != nil || tableErr != nil {
+		return // reported on apply
+	}
+	// A sandbox makes the database's view-data per table, which would end
+	// the group's connection impersonation on it.
+	if level, err := s.repository.TableViewData(ctx, groupId, tableId); err == nil && level == "impersonated" {
+		resp.Diagnostics.AddAttributeError(path.Root("table_id"), "Database Is Impersonated", fmt.Sprintf("Group %d has a connection impersonation on the database of table %d; a sandbox would remove it. Remove the group's `metabase_connection_impersonation` on that database first.", groupId, tableId))
+	}
+}
+
+// Sandbox defines the resource implementation.
+type Sandbox struct {
+	*BaseResource
+	repository *repositories.SandboxRepository
+}
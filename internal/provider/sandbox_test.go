@@ -0,0 +1,279 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testAccCheckSandboxDestroyed(s *terraform.State) error {
+	repo := repositories.NewSandboxRepository(newTestMetabaseClient())
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "metabase_sandbox" {
+			continue
+		}
+		_, err := repo.Get(context.Background(), rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("sandbox %s still exists", rs.Primary.ID)
+		}
+		var notFound *metabase.NotFoundError
+		if !errors.As(err, &notFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// testAccCheckViewDataSandboxed asserts that the group's view-data on the
+// Sample Database was set to "sandboxed" along with the sandbox.
+func testAccCheckViewDataSandboxed(s *terraform.State) error {
+	groupId := s.RootModule().Resources["metabase_permission_group.test"].Primary.ID
+	databaseId := s.RootModule().Resources["data.metabase_database.sample"].Primary.ID
+	permission, found, err := repositories.NewDatabasePermissionRepository(newTestMetabaseClient()).Get(context.Background(), groupId, databaseId)
+	if err != nil {
+		return err
+	}
+	if !found || permission.ViewData == nil || *permission.ViewData != "sandboxed" {
+		return fmt.Errorf("expected view-data of group %s on database %s to be sandboxed, got %+v", groupId, databaseId, permission)
+	}
+	return nil
+}
+
+func TestAccSandboxResource(t *testing.T) {
+	name := fmt.Sprintf("Test sandboxed group %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSampleDatabase(t)
+			testAccPreCheckTokenFeature(t, "sandboxes")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSandboxDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSandboxConfig(name, ""),
+				ExpectError: regexp.MustCompile("Set `card_id`, `attribute_remappings` or both"),
+			},
+			{
+				Config: testAccSandboxConfig(name, `
+  attribute_remappings = {
+    account = { template_tag = "account" }
+  }`),
+				ExpectError: regexp.MustCompile("`template_tag` requires `card_id`"),
+			},
+			{
+				Config: testAccSandboxConfig(name, `
+  attribute_remappings = {
+    user_id = { field_id = metabase_field_metadata.user_id.id }
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("metabase_sandbox.test", "id"),
+					resource.TestCheckResourceAttrPair("metabase_sandbox.test", "attribute_remappings.user_id.field_id", "metabase_field_metadata.user_id", "id"),
+					resource.TestCheckNoResourceAttr("metabase_sandbox.test", "card_id"),
+					testAccCheckViewDataSandboxed,
+				),
+			},
+			{
+				ResourceName:      "metabase_sandbox.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Not readable from Metabase: "blocked" on import.
+				ImportStateVerifyIgnore: []string{"previous_view_data"},
+			},
+			// The attribute is renamed in place.
+			{
+				Config: testAccSandboxConfig(name, `
+  attribute_remappings = {
+    customer_id = { field_id = metabase_field_metadata.user_id.id }
+  }`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_sandbox.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("metabase_sandbox.test", "attribute_remappings.customer_id.field_id", "metabase_field_metadata.user_id", "id"),
+					resource.TestCheckNoResourceAttr("metabase_sandbox.test", "attribute_remappings.user_id.field_id"),
+					testAccCheckViewDataSandboxed,
+				),
+			},
+			// A database-level view_data would remove the sandbox.
+			{
+				Config: testAccSandboxConfig(name, `
+  attribute_remappings = {
+    customer_id = { field_id = metabase_field_metadata.user_id.id }
+  }`) + `
+resource "metabase_database_permission" "test" {
+  group_id    = metabase_permission_group.test.id
+  database_id = data.metabase_database.sample.id
+  view_data   = "unrestricted"
+}
+`,
+				ExpectError: regexp.MustCompile("Sandboxes Would Be Removed"),
+			},
+		},
+	})
+}
+
+// testAccCheckTableViewData asserts the group's view-data level on each table
+// (keyed by table id).
+func testAccCheckTableViewData(groupId *int, levels map[*int]string) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		repo := repositories.NewSandboxRepository(newTestMetabaseClient())
+		for tableId, want := range levels {
+			got, err := repo.TableViewData(context.Background(), *groupId, *tableId)
+			if err != nil {
+				return err
+			}
+			if got != want {
+				return fmt.Errorf("expected view-data %q on table %d for group %d, got %q", want, *tableId, *groupId, got)
+			}
+		}
+		return nil
+	}
+}
+
+// TestAccSandboxResource_RestoresViewData asserts that the sandbox leaves the
+// group's other tables alone and that destroying it restores the table's
+// previous level rather than granting unrestricted access.
+func TestAccSandboxResource_RestoresViewData(t *testing.T) {
+	name := fmt.Sprintf("Test blocked group %d", rand.Int())
+	var groupId, ordersId, productsId int
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSampleDatabase(t)
+			testAccPreCheckTokenFeature(t, "sandboxes")
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSandboxDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSandboxRestoreConfig(name, false),
+				Check: func(s *terraform.State) error {
+					var err error
+					resources := s.RootModule().Resources
+					if groupId, err = strconv.Atoi(resources["metabase_permission_group.test"].Primary.ID); err != nil {
+						return err
+					}
+					if ordersId, err = strconv.Atoi(resources["data.metabase_table.orders"].Primary.ID); err != nil {
+						return err
+					}
+					productsId, err = strconv.Atoi(resources["data.metabase_table.products"].Primary.ID)
+					return err
+				},
+			},
+			// Block the group on the Sample Database out-of-band, then sandbox ORDERS.
+			{
+				PreConfig: func() {
+					client := newTestMetabaseClient()
+					database, err := repositories.NewDatabaseRepository(client).FindByName(context.Background(), "Sample Database")
+					if err != nil {
+						t.Fatalf("unable to look up the Sample Database: %s", err)
+					}
+					blocked := dtos.DatabasePermissionDTO{CreateQueries: "no", ViewData: stringPtr("blocked")}
+					if err := repositories.NewDatabasePermissionRepository(client).Set(context.Background(), strconv.Itoa(groupId), strconv.Itoa(database.Id), blocked); err != nil {
+						t.Fatalf("unable to block the group: %s", err)
+					}
+				},
+				Config: testAccSandboxRestoreConfig(name, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_sandbox.test", "previous_view_data", "blocked"),
+					testAccCheckTableViewData(&groupId, map[*int]string{&ordersId: "sandboxed", &productsId: "blocked"}),
+				),
+			},
+			// Destroy the sandbox only: ORDERS is blocked again, not unrestricted.
+			{
+				Config: testAccSandboxRestoreConfig(name, false),
+				Check:  testAccCheckTableViewData(&groupId, map[*int]string{&ordersId: "blocked", &productsId: "blocked"}),
+			},
+		},
+	})
+}
+
+func testAccSandboxRestoreConfig(groupName string, sandboxed bool) string {
+	config := testAccProviderConfig() + fmt.Sprintf(`
+data "metabase_database" "sample" {
+  name = "Sample Database"
+}
+
+data "metabase_table" "orders" {
+  database_id = data.metabase_database.sample.id
+  schema      = "PUBLIC"
+  name        = "ORDERS"
+}
+
+data "metabase_table" "products" {
+  database_id = data.metabase_database.sample.id
+  schema      = "PUBLIC"
+  name        = "PRODUCTS"
+}
+
+resource "metabase_permission_group" "test" {
+  name = %q
+}
+`, groupName)
+	if !sandboxed {
+		return config
+	}
+	return config + `
+resource "metabase_field_metadata" "user_id" {
+  table_id = data.metabase_table.orders.id
+  name     = "USER_ID"
+}
+
+resource "metabase_sandbox" "test" {
+  group_id = metabase_permission_group.test.id
+  table_id = data.metabase_table.orders.id
+  attribute_remappings = {
+    user_id = { field_id = metabase_field_metadata.user_id.id }
+  }
+}
+`
+}
+
+func testAccSandboxConfig(groupName, sandbox string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+data "metabase_database" "sample" {
+  name = "Sample Database"
+}
+
+data "metabase_table" "orders" {
+  database_id = data.metabase_database.sample.id
+  schema      = "PUBLIC"
+  name        = "ORDERS"
+}
+
+# Adopts ORDERS.USER_ID unchanged, to reference its field id.
+resource "metabase_field_metadata" "user_id" {
+  table_id = data.metabase_table.orders.id
+  name     = "USER_ID"
+}
+
+resource "metabase_permission_group" "test" {
+  name = %[1]q
+}
+
+resource "metabase_sandbox" "test" {
+  group_id = metabase_permission_group.test.id
+  table_id = data.metabase_table.orders.id
+%[2]s
+}
+`, groupName, sandbox)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+func NewTableDataSource() datasource.DataSource {
+	table := &TableDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "table",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			table.repository = repositories.NewTableRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Looks up a table discovered by Metabase's schema sync, by id or by database, schema and name.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						MarkdownDescription: "Table ID. Set either id, or database_id and name (and optionally schema).",
+						Optional:            true,
+						Computed:            true,
+					},
+					"database_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the database holding the table",
+						Optional:            true,
+						Computed:            true,
+					},
+					"schema": schema.StringAttribute{
+						MarkdownDescription: "Schema of the table, e.g. \"public\". Required when the name exists in several schemas.",
+						Optional:            true,
+						Computed:            true,
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the table in the database",
+						Optional:            true,
+						Computed:            true,
+					},
+					"display_name": schema.StringAttribute{
+						MarkdownDescription: "Name of the table shown in Metabase",
+						Computed:            true,
+					},
+					"description": schema.StringAttribute{
+						MarkdownDescription: "Description of the table",
+						Computed:            true,
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.TableTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			byId := !config.Id.IsNull()
+			byName := !config.DatabaseId.IsNull() && !config.Name.IsNull()
+			if byId == byName || byId && (!config.DatabaseId.IsNull() || !config.Name.IsNull() || !config.Schema.IsNull()) {
+				resp.Diagnostics.AddError("Invalid lookup", "Set either id, or database_id and name (and optionally schema).")
+				return
+			}
+
+			var found *dtos.TableDTO
+			var err error
+			if byId {
+				found, err = table.repository.Get(ctx, config.Id.ValueString())
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No table with id %s", config.Id.ValueString()))
+					return
+				}
+			} else {
+				found, err = table.repository.FindByName(ctx, config.DatabaseId.ValueString(), config.Schema.ValueStringPointer(), config.Name.ValueString())
+				if err == nil && found == nil {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No table named %q in database %s", config.Name.ValueString(), config.DatabaseId.ValueString()))
+					return
+				}
+			}
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get table: %s", err))
+				return
+			}
+
+			result := terraform.CreateTableTerraformModelFromDTO(found)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+	}
+
+	table.BaseDataSource = baseDataSource
+
+	return table
+}
+
+// TableDataSource defines the data source implementation.
+type TableDataSource struct {
+	*BaseDataSource
+	repository *repositories.TableRepository
+}
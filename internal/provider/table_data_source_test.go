@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccTableDataSource checks lookup validation and the not-found error
+// (which tables exist depends on when schema sync finishes).
+func TestAccTableDataSource(t *testing.T) {
+	name := fmt.Sprintf("Test table ds %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+data "metabase_table" "invalid" {
+  name = "orders"
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid lookup"),
+			},
+			{
+				Config: testAccDatabaseResourceConfig(name) + `
+data "metabase_table" "missing" {
+  database_id = metabase_database.test.id
+  name        = "no_such_table"
+}
+`,
+				ExpectError: regexp.MustCompile("No table named"),
+			},
+		},
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func NewTableMetadata() resource.Resource {
+	tableMetadata := &TableMetadata{}
+
+	baseResource := &BaseResource{
+		TypeName: "table_metadata",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			tableMetadata.repository = repositories.NewTableRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Curated metadata of a table discovered by Metabase's schema sync (the data model editor): display name, description, visibility and entity type. The table is adopted, never created or deleted: it must have been synced, and removing the resource leaves its metadata as it is. Attributes left unset keep the value Metabase has.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Table ID",
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"database_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the database holding the table",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"schema": schema.StringAttribute{
+						MarkdownDescription: "Schema of the table, e.g. \"public\". Required when the name exists in several schemas.",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplaceIfConfigured(),
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the table in the database",
+						Required:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+					},
+					"display_name": schema.StringAttribute{
+						MarkdownDescription: "Name of the table shown in Metabase",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"description": schema.StringAttribute{
+						MarkdownDescription: "Description of the table; \"\" for none",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"visibility_type": schema.StringAttribute{
+						MarkdownDescription: "\"\" (queryable), \"hidden\", \"technical\" (technical data) or \"cruft\" (irrelevant). Tables other than \"\" are hidden from the query builder and browsing.",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+						Validators:          []validator.String{OneOfValidator("", "hidden", "technical", "cruft")},
+					},
+					"entity_type": schema.StringAttribute{
+						MarkdownDescription: "What the rows are, e.g. \"entity/GenericTable\", \"entity/TransactionTable\", \"entity/UserTable\", \"entity/EventTable\" or \"entity/ProductTable\". Drives X-rays.",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+				},
+			}
+		},
+		CreateFunc: func(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+			var plan terraform.TableMetadataTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			found, err := tableMetadata.repository.FindByName(ctx, plan.DatabaseId.ValueString(), knownStringPointer(plan.Schema), plan.Name.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to look up table: %s", err))
+				return
+			}
+			if found == nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("No table named %q in database %s. Tables are only created by Metabase's schema sync: check the name or wait for the sync to finish.", plan.Name.ValueString(), plan.DatabaseId.ValueString()))
+				return
+			}
+
+			updated, err := tableMetadata.repository.UpdateMetadata(ctx, strconv.Itoa(found.Id), tableMetadataInput(plan))
+			if err != nil {
+				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to update table metadata: %s", err))
+				return
+			}
+
+			result := terraform.CreateTableMetadataTerraformModelFromDTO(updated)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		ReadFunc: func(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+			var state terraform.TableMetadataTerraformModel
+			resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			table, err := tableMetadata.repository.Get(ctx, state.Id.ValueString())
+			if err != nil {
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.State.RemoveResource(ctx)
+					return
+				}
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get table: %s", err))
+				return
+			}
+			// Dropped from the database: Metabase keeps it inactive.
+			if !table.Active {
+				resp.State.RemoveResource(ctx)
+				return
+			}
+
+			result := terraform.CreateTableMetadataTerraformModelFromDTO(table)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		UpdateFunc: func(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+			var plan terraform.TableMetadataTerraformModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			updated, err := tableMetadata.repository.UpdateMetadata(ctx, plan.Id.ValueString(), tableMetadataInput(plan))
+			if err != nil {
+				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update table metadata: %s", err))
+				return
+			}
+
+			result := terraform.CreateTableMetadataTerraformModelFromDTO(updated)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+		DeleteFunc: func(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+			// Adopt-only: the table and its metadata are left as they are.
+		},
+	}
+
+	tableMetadata.BaseResource = baseResource
+
+	return tableMetadata
+}
+
+// tableMetadataInput returns the metadata to write; unset attributes are omitted.
+func tableMetadataInput(plan terraform.TableMetadataTerraformModel) dtos.TableMetadataDTO {
+	return dtos.TableMetadataDTO{
+		DisplayName:    knownStringPointer(plan.DisplayName),
+		Description:    knownStringPointer(plan.Description),
+		VisibilityType: knownStringPointer(plan.VisibilityType),
+		EntityType:     knownStringPointer(plan.EntityType),
+	}
+}
+
+// TableMetadata defines the resource implementation.
+type TableMetadata struct {
+	*BaseResource
+	repository *repositories.TableRepository
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+// testAccPreCheckSampleDatabase skips the test unless Metabase's bundled
+// "Sample Database" (synced at setup) is present: the sample-db Postgres has
+// no tables to curate.
+func testAccPreCheckSampleDatabase(t *testing.T) {
+	db, err := repositories.NewDatabaseRepository(newTestMetabaseClient()).FindByName(context.Background(), "Sample Database")
+	if err != nil {
+		t.Fatalf("unable to look up the Sample Database: %s", err)
+	}
+	if db == nil {
+		t.Skip("requires Metabase's Sample Database")
+	}
+}
+
+func TestAccTableMetadataResource(t *testing.T) {
+	description := fmt.Sprintf("Product catalog %d", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSampleDatabase(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccTableMetadataConfig("NO_SUCH_TABLE", "Catalog", description, ""),
+				ExpectError: regexp.MustCompile("No table named"),
+			},
+			{
+				Config: testAccTableMetadataConfig("PRODUCTS", "Catalog", description, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("metabase_table_metadata.test", "database_id", "data.metabase_database.sample", "id"),
+					resource.TestCheckResourceAttr("metabase_table_metadata.test", "schema", "PUBLIC"),
+					resource.TestCheckResourceAttr("metabase_table_metadata.test", "display_name", "Catalog"),
+					resource.TestCheckResourceAttr("metabase_table_metadata.test", "description", description),
+					resource.TestCheckResourceAttr("metabase_table_metadata.test", "visibility_type", ""),
+					resource.TestCheckResourceAttrSet("metabase_table_metadata.test", "entity_type"),
+				),
+			},
+			{
+				ResourceName:      "metabase_table_metadata.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// In-place update; "" clears the description.
+			{
+				Config: testAccTableMetadataConfig("PRODUCTS", "Products", "", "technical"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_table_metadata.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_table_metadata.test", "display_name", "Products"),
+					resource.TestCheckResourceAttr("metabase_table_metadata.test", "description", ""),
+					resource.TestCheckResourceAttr("metabase_table_metadata.test", "visibility_type", "technical"),
+				),
+			},
+			// Back to queryable, so the Sample Database stays usable.
+			{
+				Config: testAccTableMetadataConfig("PRODUCTS", "Products", "", ""),
+				Check:  resource.TestCheckResourceAttr("metabase_table_metadata.test", "visibility_type", ""),
+			},
+		},
+	})
+}
+
+func testAccTableMetadataConfig(table, displayName, description, visibility string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+data "metabase_database" "sample" {
+  name = "Sample Database"
+}
+
+resource "metabase_table_metadata" "test" {
+  database_id     = data.metabase_database.sample.id
+  name            = %[1]q
+  display_name    = %[2]q
+  description     = %[3]q
+  visibility_type = %[4]q
+}
+`, table, displayName, description, visibility)
+}
@@ -8,14 +8,19 @@ import (
 	"errors"
 	"fmt"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func NewUser() resource.Resource {
@@ -58,6 +63,30 @@ func NewUser() resource.Resource {
 						Computed:            true,
 						Default:             booldefault.StaticBool(true),
 					},
+					"is_superuser": schema.BoolAttribute{
+						MarkdownDescription: "Whether the user is an admin (a member of the Administrators group). Left as Metabase has it while unset.",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+					},
+					"login_attributes": schema.MapAttribute{
+						MarkdownDescription: "User attributes, e.g. the values `metabase_sandbox` filters rows on or the database role of `metabase_connection_impersonation`. Replaces every attribute of the user (`{}` clears them); left as Metabase has them while unset.",
+						ElementType:         types.StringType,
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.Map{mapplanmodifier.UseStateForUnknown()},
+					},
+					"locale": schema.StringAttribute{
+						MarkdownDescription: "Language of the user's Metabase, e.g. \"en\", \"de\" or \"pt_BR\"; \"\" for the instance's default. Left as Metabase has it while unset.",
+						Optional:            true,
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
+					"sso_source": schema.StringAttribute{
+						MarkdownDescription: "How the user signs in through SSO: \"google\", \"ldap\", \"saml\" or \"jwt\"; null for a password login.",
+						Computed:            true,
+						PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+					},
 				},
 			}
 		},
@@ -75,8 +104,19 @@ func NewUser() resource.Resource {
 				return
 			}
 
-			createResponse, err := user.repository.Create(ctx, data.Email.ValueString(), data.FirstName.ValueString(), data.LastName.ValueString())
+			options, diags := userOptionsInput(ctx, data, nil)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			createResponse, err := user.repository.Create(ctx, data.Email.ValueString(), data.FirstName.ValueString(), data.LastName.ValueString(), options)
 			if err != nil {
+				if createResponse != nil {
+					// Keep the created user in state (tainted) so it is not orphaned.
+					result := terraform.CreateUserTerraformModelFromDTO(createResponse)
+					resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+				}
 				resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Unable to create User: %s", err))
 				return
 			}
@@ -122,11 +162,13 @@ func NewUser() resource.Resource {
 				isActive = plan.IsActive.ValueBoolPointer()
 			}
 
+			options, diags := userOptionsInput(ctx, plan, &state)
+			resp.Diagnostics.Append(diags...)
 			if resp.Diagnostics.HasError() {
 				return
 			}
 
-			_, err := user.repository.Update(ctx, plan.Id.ValueString(), plan.Email.ValueStringPointer(), plan.FirstName.ValueStringPointer(), plan.LastName.ValueStringPointer(), isActive)
+			_, err := user.repository.Update(ctx, plan.Id.ValueString(), plan.Email.ValueStringPointer(), plan.FirstName.ValueStringPointer(), plan.LastName.ValueStringPointer(), isActive, options)
 			if err != nil {
 				resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Unable to update user: %s", err))
 				return
@@ -145,7 +187,7 @@ func NewUser() resource.Resource {
 			}
 
 			isActive := false
-			_, err := user.repository.Update(ctx, state.Id.ValueString(), nil, nil, nil, &isActive)
+			_, err := user.repository.Update(ctx, state.Id.ValueString(), nil, nil, nil, &isActive, dtos.UserOptionsDTO{})
 			if err != nil {
 				resp.Diagnostics.AddError("Deactivate Error", fmt.Sprintf("Unable to deactivate user: %s", err))
 				return
@@ -158,6 +200,33 @@ func NewUser() resource.Resource {
 	return user
 }
 
+// userOptionsInput returns the options to write: on create (nil state) every
+// known value, on update only the changed ones.
+func userOptionsInput(ctx context.Context, plan terraform.UserTerraformModel, state *terraform.UserTerraformModel) (dtos.UserOptionsDTO, diag.Diagnostics) {
+	prior := terraform.UserTerraformModel{
+		IsSuperuser:     types.BoolNull(),
+		LoginAttributes: types.MapNull(types.StringType),
+		Locale:          types.StringNull(),
+	}
+	if state != nil {
+		prior = *state
+	}
+
+	var options dtos.UserOptionsDTO
+	if !plan.IsSuperuser.Equal(prior.IsSuperuser) {
+		options.IsSuperuser = knownBoolPointer(plan.IsSuperuser)
+	}
+	if !plan.Locale.Equal(prior.Locale) {
+		options.Locale = knownStringPointer(plan.Locale)
+	}
+	var diags diag.Diagnostics
+	if !plan.LoginAttributes.IsNull() && !plan.LoginAttributes.IsUnknown() && !plan.LoginAttributes.Equal(prior.LoginAttributes) {
+		options.LoginAttributes = map[string]string{}
+		diags.Append(plan.LoginAttributes.ElementsAs(ctx, &options.LoginAttributes, false)...)
+	}
+	return options, diags
+}
+
 // User defines the resource implementation.
 type User struct {
 	*BaseResource
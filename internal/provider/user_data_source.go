@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewUserDataSource() datasource.DataSource {
+	user := &UserDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "user",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			user.repository = repositories.NewUserRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Looks up an existing user, active or deactivated, by id or by email.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						MarkdownDescription: "User ID. Set exactly one of id or email.",
+						Optional:            true,
+						Computed:            true,
+					},
+					"email": schema.StringAttribute{
+						MarkdownDescription: "Email of the user (case-insensitive). Set exactly one of id or email.",
+						Optional:            true,
+						Computed:            true,
+					},
+					"first_name": schema.StringAttribute{
+						MarkdownDescription: "First name of the user",
+						Computed:            true,
+					},
+					"last_name": schema.StringAttribute{
+						MarkdownDescription: "Last name of the user",
+						Computed:            true,
+					},
+					"is_active": schema.BoolAttribute{
+						MarkdownDescription: "Whether the user is active (false once deactivated)",
+						Computed:            true,
+					},
+					"is_superuser": schema.BoolAttribute{
+						MarkdownDescription: "Whether the user is an admin",
+						Computed:            true,
+					},
+					"login_attributes": schema.MapAttribute{
+						MarkdownDescription: "User attributes",
+						ElementType:         types.StringType,
+						Computed:            true,
+					},
+					"locale": schema.StringAttribute{
+						MarkdownDescription: "Language of the user's Metabase; \"\" for the instance's default",
+						Computed:            true,
+					},
+					"sso_source": schema.StringAttribute{
+						MarkdownDescription: "How the user signs in through SSO; null for a password login",
+						Computed:            true,
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.UserTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if config.Id.IsNull() == config.Email.IsNull() {
+				resp.Diagnostics.AddError("Invalid lookup", "Set exactly one of id or email.")
+				return
+			}
+
+			var found *dtos.UserDTO
+			var err error
+			if !config.Id.IsNull() {
+				found, err = user.repository.Get(ctx, config.Id.ValueString())
+				var notFound *metabase.NotFoundError
+				if errors.As(err, &notFound) {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No user with id %s", config.Id.ValueString()))
+					return
+				}
+			} else {
+				found, err = user.repository.FindByEmail(ctx, config.Email.ValueString())
+				if err == nil && found == nil {
+					resp.Diagnostics.AddError("Not Found", fmt.Sprintf("No user with email %q", config.Email.ValueString()))
+					return
+				}
+			}
+			if err != nil {
+				resp.Diagnostics.AddError("Get Error", fmt.Sprintf("Unable to get user: %s", err))
+				return
+			}
+
+			result := terraform.CreateUserTerraformModelFromDTO(found)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+		},
+	}
+
+	user.BaseDataSource = baseDataSource
+
+	return user
+}
+
+// UserDataSource defines the data source implementation.
+type UserDataSource struct {
+	*BaseDataSource
+	repository *repositories.UserRepository
+}
@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccUserDataSource looks up a user by email, in a different case.
+func TestAccUserDataSource(t *testing.T) {
+	email := fmt.Sprintf("ds-user-%d@example.com", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceConfig(email, "Data", "Source") + `
+data "metabase_user" "test" {
+  email = upper(metabase_user.test.email)
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.metabase_user.test", "id", "metabase_user.test", "id"),
+					resource.TestCheckResourceAttr("data.metabase_user.test", "first_name", "Data"),
+					resource.TestCheckResourceAttr("data.metabase_user.test", "is_active", "true"),
+				),
+			},
+		},
+	})
+}
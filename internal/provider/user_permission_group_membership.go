@@ -27,7 +27,7 @@ func NewUserPermissionGroupMembership() resource.Resource {
 		GetSchema: func(ctx context.Context) schema.Schema {
 			return schema.Schema{
 				// This description is used by the documentation generator and the language server.
-				MarkdownDescription: "Represents the link between a specific user and a permission group. Users can be members of multiple groups, and their effective permissions are the union of all permissions granted to the groups they belong to.",
+				MarkdownDescription: "Represents the link between a specific user and a permission group. Users can be members of multiple groups, and their effective permissions are the union of all permissions granted to the groups they belong to. To manage every member of a group at once, use `metabase_permission_group_members` instead.",
 
 				Attributes: map[string]schema.Attribute{
 					"id": schema.StringAttribute{
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -143,7 +144,7 @@ func TestAccUserPermissionGroupMembershipResource_survivesUserDeactivation(t *te
 					memRepo := repositories.NewUserPermissionGroupMembershipRepository(newTestMetabaseClient())
 
 					// Deactivate the user out-of-band.
-					if _, err := userRepo.Update(context.Background(), userID, nil, nil, nil, boolPtr(false)); err != nil {
+					if _, err := userRepo.Update(context.Background(), userID, nil, nil, nil, boolPtr(false), dtos.UserOptionsDTO{}); err != nil {
 						return fmt.Errorf("deactivate failed: %w", err)
 					}
 					// Membership must still exist.
@@ -151,7 +152,7 @@ func TestAccUserPermissionGroupMembershipResource_survivesUserDeactivation(t *te
 						return fmt.Errorf("membership %s vanished after user deactivation: %w", membershipID, err)
 					}
 					// Reactivate so the test's destroy phase is clean.
-					if _, err := userRepo.Update(context.Background(), userID, nil, nil, nil, boolPtr(true)); err != nil {
+					if _, err := userRepo.Update(context.Background(), userID, nil, nil, nil, boolPtr(true), dtos.UserOptionsDTO{}); err != nil {
 						return fmt.Errorf("reactivate failed: %w", err)
 					}
 					return nil
@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
@@ -29,6 +30,9 @@ var emailChanged = getUserEmail()
 func boolPtr(b bool) *bool { return &b }
 
 func newTestMetabaseClient() *metabase.MetabaseAPIClient {
+	if username := os.Getenv("METABASE_USERNAME"); username != "" && os.Getenv("METABASE_API_KEY") == "" {
+		return metabase.NewMetabaseAPIClient(os.Getenv("METABASE_HOST"), "", metabase.WithSessionCredentials(username, os.Getenv("METABASE_PASSWORD")))
+	}
 	return metabase.NewMetabaseAPIClient(os.Getenv("METABASE_HOST"), os.Getenv("METABASE_API_KEY"))
 }
 
@@ -114,6 +118,64 @@ func TestAccUserResource(t *testing.T) {
 	})
 }
 
+// TestAccUserResource_Attributes covers is_superuser, login_attributes and
+// locale, including drift: a user demoted out-of-band is promoted again.
+func TestAccUserResource_Attributes(t *testing.T) {
+	attributesEmail := getUserEmail()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserDeactivated,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceAttributesConfig(attributesEmail, true, `{ account_id = "42", region = "emea" }`, "de"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_user.test", "is_superuser", "true"),
+					resource.TestCheckResourceAttr("metabase_user.test", "login_attributes.%", "2"),
+					resource.TestCheckResourceAttr("metabase_user.test", "login_attributes.account_id", "42"),
+					resource.TestCheckResourceAttr("metabase_user.test", "locale", "de"),
+					resource.TestCheckNoResourceAttr("metabase_user.test", "sso_source"),
+				),
+			},
+			{
+				ResourceName:      "metabase_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Demoted out-of-band: the drift is planned back.
+			{
+				PreConfig: func() {
+					repo := repositories.NewUserRepository(newTestMetabaseClient())
+					u, err := repo.FindByEmail(context.Background(), attributesEmail)
+					if err != nil || u == nil {
+						t.Fatalf("precondition: lookup failed: %v", err)
+					}
+					if _, err := repo.Update(context.Background(), strconv.Itoa(u.Id), nil, nil, nil, nil, dtos.UserOptionsDTO{IsSuperuser: boolPtr(false)}); err != nil {
+						t.Fatalf("precondition: demote failed: %s", err)
+					}
+				},
+				Config: testAccUserResourceAttributesConfig(attributesEmail, true, `{ account_id = "42", region = "emea" }`, "de"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("metabase_user.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("metabase_user.test", "is_superuser", "true"),
+			},
+			// Demote, clear the attributes and go back to the default locale.
+			{
+				Config: testAccUserResourceAttributesConfig(attributesEmail, false, "{}", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("metabase_user.test", "is_superuser", "false"),
+					resource.TestCheckResourceAttr("metabase_user.test", "login_attributes.%", "0"),
+					resource.TestCheckResourceAttr("metabase_user.test", "locale", ""),
+				),
+			},
+		},
+	})
+}
+
 // TestAccUserResource_lowercaseEmailValidation asserts a mixed-case email is
 // rejected at plan time (before any API call), so no orphan user is created.
 func TestAccUserResource_lowercaseEmailValidation(t *testing.T) {
@@ -144,11 +206,11 @@ func TestAccUserResource_existingEmailErrorsWithImportHint(t *testing.T) {
 				// Pre-create the user out-of-band and deactivate it.
 				PreConfig: func() {
 					repo := repositories.NewUserRepository(newTestMetabaseClient())
-					u, err := repo.Create(context.Background(), reuseEmail, "Pre", "Existing")
+					u, err := repo.Create(context.Background(), reuseEmail, "Pre", "Existing", dtos.UserOptionsDTO{})
 					if err != nil {
 						t.Fatalf("precondition: create failed: %s", err)
 					}
-					if _, err := repo.Update(context.Background(), strconv.Itoa(u.Id), nil, nil, nil, boolPtr(false)); err != nil {
+					if _, err := repo.Update(context.Background(), strconv.Itoa(u.Id), nil, nil, nil, boolPtr(false), dtos.UserOptionsDTO{}); err != nil {
 						t.Fatalf("precondition: deactivate failed: %s", err)
 					}
 				},
@@ -175,12 +237,12 @@ func TestAccUserResource_reactivateViaImport(t *testing.T) {
 			{
 				PreConfig: func() {
 					repo := repositories.NewUserRepository(newTestMetabaseClient())
-					u, err := repo.Create(context.Background(), reuseEmail, "Imp", "Ported")
+					u, err := repo.Create(context.Background(), reuseEmail, "Imp", "Ported", dtos.UserOptionsDTO{})
 					if err != nil {
 						t.Fatalf("precondition: create failed: %s", err)
 					}
 					preID = strconv.Itoa(u.Id)
-					if _, err := repo.Update(context.Background(), preID, nil, nil, nil, boolPtr(false)); err != nil {
+					if _, err := repo.Update(context.Background(), preID, nil, nil, nil, boolPtr(false), dtos.UserOptionsDTO{}); err != nil {
 						t.Fatalf("precondition: deactivate failed: %s", err)
 					}
 				},
@@ -227,19 +289,19 @@ func TestUserReactivateIdempotent(t *testing.T) {
 	repo := repositories.NewUserRepository(newTestMetabaseClient())
 	ctx := context.Background()
 
-	created, err := repo.Create(ctx, getUserEmail(), "Reactivate", "Idempotent")
+	created, err := repo.Create(ctx, getUserEmail(), "Reactivate", "Idempotent", dtos.UserOptionsDTO{})
 	if err != nil {
 		t.Fatalf("create failed: %s", err)
 	}
 	id := strconv.Itoa(created.Id)
 
 	// The user is active; reactivating it (Update with is_active=true) must succeed.
-	if _, err := repo.Update(ctx, id, nil, nil, nil, boolPtr(true)); err != nil {
+	if _, err := repo.Update(ctx, id, nil, nil, nil, boolPtr(true), dtos.UserOptionsDTO{}); err != nil {
 		t.Fatalf("reactivating an active user should be idempotent, got: %s", err)
 	}
 
 	// Cleanup: deactivate.
-	if _, err := repo.Update(ctx, id, nil, nil, nil, boolPtr(false)); err != nil {
+	if _, err := repo.Update(ctx, id, nil, nil, nil, boolPtr(false), dtos.UserOptionsDTO{}); err != nil {
 		t.Fatalf("cleanup deactivate failed: %s", err)
 	}
 }
@@ -268,3 +330,16 @@ resource "metabase_user" "test" {
 }
 `, email, firstName, lastName)
 }
+
+func testAccUserResourceAttributesConfig(email string, isSuperuser bool, loginAttributes string, locale string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_user" "test" {
+  email            = %q
+  first_name       = "Attributes"
+  last_name        = "User"
+  is_superuser     = %t
+  login_attributes = %s
+  locale           = %q
+}
+`, email, isSuperuser, loginAttributes, locale)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/terraform"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/repositories"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewUsersDataSource() datasource.DataSource {
+	users := &UsersDataSource{}
+
+	baseDataSource := &BaseDataSource{
+		TypeName: "users",
+		ConfigureRepository: func(client *metabase.MetabaseAPIClient) {
+			users.repository = repositories.NewUserRepository(client)
+		},
+		GetSchema: func(ctx context.Context) schema.Schema {
+			return schema.Schema{
+				MarkdownDescription: "Lists users, optionally filtered by status and email, e.g. to `for_each` over everyone in a domain.",
+				Attributes: map[string]schema.Attribute{
+					"status": schema.StringAttribute{
+						MarkdownDescription: "Which users to list: \"active\" (default), \"deactivated\" or \"all\".",
+						Optional:            true,
+						Validators:          []validator.String{OneOfValidator("active", "deactivated", "all")},
+					},
+					"email_regex": schema.StringAttribute{
+						MarkdownDescription: "Only list users whose email matches this regular expression, e.g. `\"@example\\\\.com$\"`.",
+						Optional:            true,
+						Validators:          []validator.String{RegexValidator()},
+					},
+					"users": schema.ListNestedAttribute{
+						MarkdownDescription: "Matching users, ordered by ID",
+						Computed:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									MarkdownDescription: "User ID",
+									Computed:            true,
+								},
+								"email": schema.StringAttribute{
+									MarkdownDescription: "Email of the user",
+									Computed:            true,
+								},
+								"first_name": schema.StringAttribute{
+									MarkdownDescription: "First name of the user",
+									Computed:            true,
+								},
+								"last_name": schema.StringAttribute{
+									MarkdownDescription: "Last name of the user",
+									Computed:            true,
+								},
+								"is_active": schema.BoolAttribute{
+									MarkdownDescription: "Whether the user is active",
+									Computed:            true,
+								},
+								"is_superuser": schema.BoolAttribute{
+									MarkdownDescription: "Whether the user is an admin",
+									Computed:            true,
+								},
+								"login_attributes": schema.MapAttribute{
+									MarkdownDescription: "User attributes",
+									ElementType:         types.StringType,
+									Computed:            true,
+								},
+								"locale": schema.StringAttribute{
+									MarkdownDescription: "Language of the user's Metabase; \"\" for the instance's default",
+									Computed:            true,
+								},
+								"sso_source": schema.StringAttribute{
+									MarkdownDescription: "How the user signs in through SSO; null for a password login",
+									Computed:            true,
+								},
+							},
+						},
+					},
+				},
+			}
+		},
+		ReadFunc: func(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+			var config terraform.UsersDataSourceTerraformModel
+			resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			emailRegex, err := optionalRegex(config.EmailRegex)
+			if err != nil {
+				resp.Diagnostics.AddError("Invalid email_regex", err.Error())
+				return
+			}
+			status := "active"
+			if !config.Status.IsNull() {
+				status = config.Status.ValueString()
+			}
+
+			list, err := users.repository.List(ctx, status)
+			if err != nil {
+				resp.Diagnostics.AddError("List Error", fmt.Sprintf("Unable to list users: %s", err))
+				return
+			}
+			sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+
+			config.Users = []terraform.UserTerraformModel{}
+			for i := range list {
+				if matchesRegex(emailRegex, list[i].Email) {
+					config.Users = append(config.Users, terraform.CreateUserTerraformModelFromDTO(&list[i]))
+				}
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+		},
+	}
+
+	users.BaseDataSource = baseDataSource
+
+	return users
+}
+
+// UsersDataSource defines the data source implementation.
+type UsersDataSource struct {
+	*BaseDataSource
+	repository *repositories.UserRepository
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccUsersDataSource lists the users of a per-test domain, and checks that
+// a deactivated one is only listed with status = "all".
+func TestAccUsersDataSource(t *testing.T) {
+	domain := fmt.Sprintf("ds%d.example.com", rand.Int())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "metabase_user" "active" {
+  email      = "active@%[1]s"
+  first_name = "Active"
+  last_name  = "User"
+}
+
+resource "metabase_user" "deactivated" {
+  email      = "deactivated@%[1]s"
+  first_name = "Deactivated"
+  last_name  = "User"
+  is_active  = false
+}
+
+data "metabase_users" "active" {
+  email_regex = "@%[1]s$"
+  depends_on  = [metabase_user.active, metabase_user.deactivated]
+}
+
+data "metabase_users" "all" {
+  status      = "all"
+  email_regex = "@%[1]s$"
+  depends_on  = [metabase_user.active, metabase_user.deactivated]
+}
+`, domain),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.metabase_users.active", "users.#", "1"),
+					resource.TestCheckResourceAttrPair("data.metabase_users.active", "users.0.id", "metabase_user.active", "id"),
+					resource.TestCheckResourceAttr("data.metabase_users.all", "users.#", "2"),
+				),
+			},
+		},
+	})
+}
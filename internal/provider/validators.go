@@ -5,10 +5,15 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // lowercaseValidator rejects non-lowercase values: Metabase lowercases emails
@@ -74,3 +79,163 @@ func (v oneOfValidator) ValidateString(_ context.Context, req validator.StringRe
 		fmt.Sprintf("%q must be one of: %s", value, strings.Join(v.allowed, ", ")),
 	)
 }
+
+// durationValidator requires a Go duration string such as "500ms" or "30s".
+type durationValidator struct{}
+
+// DurationValidator returns a validator that accepts positive Go duration strings.
+func DurationValidator() validator.String {
+	return durationValidator{}
+}
+
+func (v durationValidator) Description(_ context.Context) string {
+	return "value must be a positive duration such as \"500ms\" or \"30s\""
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	value := req.ConfigValue.ValueString()
+	if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			fmt.Sprintf("%q is not a positive duration; use a value such as \"500ms\", \"30s\" or \"2m\".", value),
+		)
+	}
+}
+
+// cronScheduleValidator requires a cron expression Metabase can schedule.
+type cronScheduleValidator struct{}
+
+// CronScheduleValidator returns a validator that accepts the hourly, daily,
+// weekly and monthly cron expressions of metabase.ParseCronSchedule.
+func CronScheduleValidator() validator.String {
+	return cronScheduleValidator{}
+}
+
+func (v cronScheduleValidator) Description(_ context.Context) string {
+	return "value must be an hourly, daily, weekly or monthly Quartz cron expression such as \"0 0 2 * * ? *\""
+}
+
+func (v cronScheduleValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cronScheduleValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := metabase.ParseCronSchedule(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid schedule",
+			fmt.Sprintf("%s. Metabase only runs hourly, daily, weekly or monthly schedules.", err),
+		)
+	}
+}
+
+// jsonValidator requires a JSON document (object or array).
+type jsonValidator struct{}
+
+// JSONValidator returns a validator that accepts a JSON object or array, as
+// produced by jsonencode.
+func JSONValidator() validator.String {
+	return jsonValidator{}
+}
+
+func (v jsonValidator) Description(_ context.Context) string {
+	return "value must be a JSON object or array (use jsonencode)"
+}
+
+func (v jsonValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	var decoded any
+	err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &decoded)
+	if err == nil {
+		switch decoded.(type) {
+		case map[string]any, []any:
+			return
+		}
+		err = fmt.Errorf("got a JSON scalar")
+	}
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid JSON",
+		fmt.Sprintf("Expected a JSON object or array (use jsonencode): %s", err),
+	)
+}
+
+// mapValuesOneOfValidator restricts every value of a map of strings to a fixed
+// set of allowed values.
+type mapValuesOneOfValidator struct{ allowed []string }
+
+// MapValuesOneOfValidator returns a validator that accepts only maps whose
+// values are among the given values.
+func MapValuesOneOfValidator(allowed ...string) validator.Map {
+	return mapValuesOneOfValidator{allowed: allowed}
+}
+
+func (v mapValuesOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("values must be one of %s", strings.Join(v.allowed, ", "))
+}
+
+func (v mapValuesOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v mapValuesOneOfValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for key, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		elementResp := &validator.StringResponse{}
+		oneOfValidator(v).ValidateString(ctx, validator.StringRequest{Path: req.Path.AtMapKey(key), ConfigValue: value}, elementResp)
+		resp.Diagnostics.Append(elementResp.Diagnostics...)
+	}
+}
+
+// regexValidator requires a valid regular expression (RE2 syntax, like
+// Terraform's regex function).
+type regexValidator struct{}
+
+// RegexValidator returns a validator that requires a valid regular expression.
+func RegexValidator() validator.String {
+	return regexValidator{}
+}
+
+func (v regexValidator) Description(_ context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid regular expression",
+			fmt.Sprintf("%q is not a valid regular expression: %s", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
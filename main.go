@@ -8,6 +8,7 @@ import (
 	"flag"
 	"github.com/csp33/terraform-provider-metabase/internal/provider"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
@@ -34,7 +35,13 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	ctx := context.Background()
+	err := providerserver.Serve(ctx, provider.New(version), opts)
+
+	// End username/password sessions once Terraform stops the plugin.
+	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	provider.Shutdown(shutdownCtx)
+	cancel()
 
 	if err != nil {
 		log.Fatal(err.Error())
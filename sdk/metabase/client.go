@@ -4,59 +4,148 @@
 package metabase
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"sync"
+	"time"
 )
 
 type MetabaseAPIClient struct {
 	Host   string
 	APIKey string
-	Client *http.Client
+	// Username and Password enable session authentication (see WithSessionCredentials).
+	Username string
+	Password string
+	Client   *http.Client
+	// Headers are added to every request (see WithHeaders).
+	Headers map[string]string
+	// RetryPolicy controls retries of transient failures; the zero value disables them.
+	RetryPolicy RetryPolicy
+	// Logger receives request logs (see WithLogger); nil disables logging.
+	Logger Logger
+	// ServerInfo is the instance version and edition once FetchServerInfo ran.
+	ServerInfo *ServerInfo
+
+	sessionMu    sync.Mutex
+	sessionToken string
 }
 
-func NewMetabaseAPIClient(host, apiKey string) *MetabaseAPIClient {
-	return &MetabaseAPIClient{
-		Host:   host,
-		APIKey: apiKey,
-		Client: &http.Client{},
+// ClientOption customizes a MetabaseAPIClient built by NewMetabaseAPIClient.
+type ClientOption func(*MetabaseAPIClient)
+
+func NewMetabaseAPIClient(host, apiKey string, opts ...ClientOption) *MetabaseAPIClient {
+	client := &MetabaseAPIClient{
+		Host:        host,
+		APIKey:      apiKey,
+		Client:      &http.Client{},
+		RetryPolicy: DefaultRetryPolicy(),
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
 }
 
 func (m *MetabaseAPIClient) request(ctx context.Context, path string, body any, method string) (*http.Response, error) {
-	var bodyReader io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		bodyReader = strings.NewReader(string(jsonBody))
 	}
 
-	url := fmt.Sprintf("%s%s", m.Host, path)
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-	if err != nil {
-		return nil, err
+	if !m.usesSession() {
+		return m.send(ctx, method, path, jsonBody, map[string]string{"x-api-key": m.APIKey})
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", m.APIKey)
-
-	resp, err := m.Client.Do(req)
+	token, err := m.session(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode/100 != 2 {
-		defer resp.Body.Close()
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		message := string(bodyBytes)
-		return nil, CreateErrorFromStatusCode(resp.StatusCode, message)
+	resp, err := m.send(ctx, method, path, jsonBody, map[string]string{sessionHeader: token})
+	var unauthorized *UnauthorizedError
+	if errors.As(err, &unauthorized) {
+		// The session expired or was revoked: log in again and replay once.
+		token, err = m.renewSession(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = m.send(ctx, method, path, jsonBody, map[string]string{sessionHeader: token})
 	}
+	return resp, err
+}
 
-	return resp, nil
+// send performs one logical request, retrying transient failures per RetryPolicy.
+func (m *MetabaseAPIClient) send(ctx context.Context, method string, path string, jsonBody []byte, headers map[string]string) (*http.Response, error) {
+	url := fmt.Sprintf("%s%s", m.Host, path)
+	maxRetries := m.RetryPolicy.maxRetries(ctx)
+	for attempt := 1; ; attempt++ {
+		var bodyReader io.Reader
+		if jsonBody != nil {
+			bodyReader = bytes.NewReader(jsonBody)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range m.Headers {
+			req.Header.Set(key, value)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		start := time.Now()
+		resp, err := m.Client.Do(req)
+		var respBody []byte
+		if err == nil {
+			respBody, err = m.bufferBody(ctx, resp)
+			if err != nil {
+				resp = nil // closed by bufferBody
+			}
+		}
+		m.logAttempt(ctx, req, jsonBody, resp, respBody, err, attempt, time.Since(start))
+		if attempt <= maxRetries && shouldRetry(method, resp, err) {
+			var header http.Header
+			if resp != nil {
+				header = resp.Header
+			}
+			wait := m.RetryPolicy.backoff(attempt, header)
+			if resp != nil {
+				// Drain so the connection can be reused.
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode/100 != 2 {
+			defer resp.Body.Close()
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			message := string(bodyBytes)
+			err := CreateErrorFromStatusCode(resp.StatusCode, message)
+			if apiErr, ok := err.(apiError); ok {
+				apiErr.base().Header = resp.Header
+			}
+			return nil, err
+		}
+
+		return resp, nil
+	}
 }
 
 func (m *MetabaseAPIClient) Post(ctx context.Context, path string, body any) (*http.Response, error) {
@@ -7,9 +7,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"syscall"
 	"testing"
+	"time"
 )
 
 type mockHTTPClient struct {
@@ -256,3 +259,161 @@ func TestMetabaseAPIClient_ErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+// sequenceTransport replies with each response (or error) in turn and counts attempts.
+type sequenceTransport struct {
+	replies  []func() (*http.Response, error)
+	attempts int
+}
+
+func (s *sequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reply := s.replies[min(s.attempts, len(s.replies)-1)]
+	s.attempts++
+	return reply()
+}
+
+func status(code int, headers ...string) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: code,
+			Header:     http.Header{},
+			Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			resp.Header.Set(headers[i], headers[i+1])
+		}
+		return resp, nil
+	}
+}
+
+func newRetryTestClient(transport http.RoundTripper) *MetabaseAPIClient {
+	return &MetabaseAPIClient{
+		Host:        "http://localhost:3000",
+		APIKey:      "test-key",
+		Client:      &http.Client{Transport: transport},
+		RetryPolicy: RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+	}
+}
+
+func TestMetabaseAPIClient_Retry(t *testing.T) {
+	resetErr := func() (*http.Response, error) { return nil, syscall.ECONNRESET }
+
+	tests := []struct {
+		name         string
+		method       string
+		replies      []func() (*http.Response, error)
+		wantErr      bool
+		wantAttempts int
+	}{
+		{"5xx on GET is retried", http.MethodGet, []func() (*http.Response, error){status(502), status(503), status(200)}, false, 3},
+		{"5xx on PUT is retried", http.MethodPut, []func() (*http.Response, error){status(500), status(200)}, false, 2},
+		{"5xx on POST is not retried", http.MethodPost, []func() (*http.Response, error){status(502), status(200)}, true, 1},
+		{"429 on POST is retried", http.MethodPost, []func() (*http.Response, error){status(429, "Retry-After", "0"), status(201)}, false, 2},
+		{"network error on GET is retried", http.MethodGet, []func() (*http.Response, error){resetErr, status(200)}, false, 2},
+		{"4xx is not retried", http.MethodGet, []func() (*http.Response, error){status(404), status(200)}, true, 1},
+		{"retries are bounded", http.MethodGet, []func() (*http.Response, error){status(502)}, true, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &sequenceTransport{replies: tt.replies}
+			client := newRetryTestClient(transport)
+
+			var body any
+			if tt.method == http.MethodPost || tt.method == http.MethodPut {
+				body = map[string]string{"name": "test"}
+			}
+			_, err := client.request(context.Background(), "/api/items", body, tt.method)
+
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if transport.attempts != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, transport.attempts)
+			}
+		})
+	}
+}
+
+func TestMetabaseAPIClient_WithoutRetries(t *testing.T) {
+	transport := &sequenceTransport{replies: []func() (*http.Response, error){status(503), status(200)}}
+	client := newRetryTestClient(transport)
+
+	_, err := client.Put(WithoutRetries(context.Background()), "/api/items/1", map[string]string{"name": "x"})
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+	if transport.attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", transport.attempts)
+	}
+}
+
+// TestMetabaseAPIClient_WaitBeforeRetry checks that a caller-level retry
+// waits for the Retry-After carried by the error, capped like the client's.
+func TestMetabaseAPIClient_WaitBeforeRetry(t *testing.T) {
+	transport := &sequenceTransport{replies: []func() (*http.Response, error){status(429, "Retry-After", "1")}}
+	client := newRetryTestClient(transport)
+	client.RetryPolicy.MaxBackoff = 20 * time.Millisecond
+
+	_, err := client.Put(WithoutRetries(context.Background()), "/api/items/1", map[string]string{"name": "x"})
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+	if wait := client.RetryPolicy.backoff(1, errorHeader(err)); wait != 20*time.Millisecond {
+		t.Errorf("expected the Retry-After capped at 20ms, got %s", wait)
+	}
+
+	start := time.Now()
+	if err := client.WaitBeforeRetry(context.Background(), 1, err); err != nil {
+		t.Fatalf("WaitBeforeRetry failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected to wait at least 20ms, waited %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.WaitBeforeRetry(ctx, 1, err); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMetabaseAPIClient_RetryResendsBody(t *testing.T) {
+	var bodies []string
+	transport := &sequenceTransport{replies: []func() (*http.Response, error){status(502), status(200)}}
+	client := newRetryTestClient(&mockHTTPClient{RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+		return transport.RoundTrip(req)
+	}})
+
+	if _, err := client.Put(context.Background(), "/api/items/1", map[string]string{"name": "x"}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Errorf("expected the same body on every attempt, got %q", bodies)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retry, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 6: time.Second} {
+		wait := policy.backoff(retry, nil)
+		if wait < want/2 || wait > want {
+			t.Errorf("retry %d: expected wait in [%s, %s], got %s", retry, want/2, want, wait)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+	if wait := policy.backoff(1, resp.Header); wait != time.Second {
+		t.Errorf("expected Retry-After of 1s to be honoured, got %s", wait)
+	}
+	resp.Header.Set("Retry-After", "120")
+	if wait := policy.backoff(1, resp.Header); wait != time.Second {
+		t.Errorf("expected Retry-After to be capped at MaxBackoff, got %s", wait)
+	}
+}
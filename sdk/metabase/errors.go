@@ -4,6 +4,7 @@
 package metabase
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -12,12 +13,33 @@ import (
 type BaseError struct {
 	StatusCode int
 	Message    string
+	// Header holds the response headers, e.g. Retry-After (see WaitBeforeRetry).
+	Header http.Header
 }
 
 func (e *BaseError) Error() string {
 	return fmt.Sprintf("%s (status code: %d)", e.Message, e.StatusCode)
 }
 
+func (e *BaseError) base() *BaseError {
+	return e
+}
+
+// apiError matches BaseError and every error type embedding it.
+type apiError interface {
+	error
+	base() *BaseError
+}
+
+// errorHeader returns the response headers carried by an API error, if any.
+func errorHeader(err error) http.Header {
+	var apiErr apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.base().Header
+	}
+	return nil
+}
+
 // NotFoundError is returned when a resource is not found (404).
 type NotFoundError struct {
 	BaseError
@@ -124,3 +146,15 @@ func CreateErrorFromStatusCode(statusCode int, message string) error {
 		}
 	}
 }
+
+// UnsupportedError is returned before calling the API when the instance's
+// version or edition lacks a feature (see MetabaseAPIClient.Require).
+type UnsupportedError struct {
+	Feature string
+	Needs   string
+	Server  string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires %s (this instance runs %s)", e.Feature, e.Needs, e.Server)
+}
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Logger receives one entry per HTTP attempt. Debug entries carry method,
+// path, status, latency and attempt number; Trace entries add the headers
+// and bodies, with credentials and secrets masked. Fields are flat so they
+// map onto tflog and most structured loggers.
+type Logger interface {
+	Debug(ctx context.Context, msg string, fields map[string]any)
+	Trace(ctx context.Context, msg string, fields map[string]any)
+}
+
+// TraceEnabler is an optional Logger extension. When a logger implements it,
+// response bodies are only buffered and redacted while TraceEnabled is true;
+// otherwise they always are.
+type TraceEnabler interface {
+	TraceEnabled(ctx context.Context) bool
+}
+
+// WithLogger sets the logger used for request logs; without one nothing is logged.
+func WithLogger(logger Logger) ClientOption {
+	return func(m *MetabaseAPIClient) {
+		m.Logger = logger
+	}
+}
+
+const redactedValue = "**REDACTED**"
+
+// credentialHeaders are always masked; the values of Headers are masked too,
+// since extra headers typically authenticate against a proxy.
+var credentialHeaders = []string{"x-api-key", sessionHeader, "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// secretKeyFragments mark a JSON key as secret when it contains one of them,
+// e.g. "password", "tunnel-pass", "service-account-json", "private-key".
+var secretKeyFragments = []string{"pass", "secret", "token", "private-key", "service-account-json", "api-key", "api_key", "credential"}
+
+// logAttempt reports one HTTP attempt; respBody is the buffered response body.
+func (m *MetabaseAPIClient) logAttempt(ctx context.Context, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error, attempt int, latency time.Duration) {
+	if m.Logger == nil {
+		return
+	}
+
+	fields := map[string]any{
+		"method":     req.Method,
+		"path":       req.URL.RequestURI(),
+		"attempt":    attempt,
+		"latency_ms": latency.Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	} else {
+		fields["status"] = resp.StatusCode
+	}
+	m.Logger.Debug(ctx, "Metabase API request", fields)
+	if !m.traceEnabled(ctx) {
+		return
+	}
+
+	// The session endpoint returns the token as "id".
+	extraSecrets := []string{}
+	if req.URL.Path == "/api/session" {
+		extraSecrets = append(extraSecrets, "id")
+	}
+	traceFields := make(map[string]any, len(fields)+3)
+	for k, v := range fields {
+		traceFields[k] = v
+	}
+	traceFields["request_headers"] = m.redactHeaders(req.Header)
+	if len(reqBody) > 0 {
+		traceFields["request_body"] = redactBody(reqBody)
+	}
+	if resp != nil {
+		traceFields["response_body"] = redactBody(respBody, extraSecrets...)
+	}
+	m.Logger.Trace(ctx, "Metabase API request details", traceFields)
+}
+
+func (m *MetabaseAPIClient) traceEnabled(ctx context.Context) bool {
+	if m.Logger == nil {
+		return false
+	}
+	if t, ok := m.Logger.(TraceEnabler); ok {
+		return t.TraceEnabled(ctx)
+	}
+	return true
+}
+
+// bufferBody reads the response body so it can be logged, leaving an
+// equivalent reader in its place. A no-op unless trace logging is enabled.
+// A failed read (e.g. a body truncated mid-read) is returned, so that the
+// attempt fails as a network error.
+func (m *MetabaseAPIClient) bufferBody(ctx context.Context, resp *http.Response) ([]byte, error) {
+	if !m.traceEnabled(ctx) || resp == nil || resp.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (m *MetabaseAPIClient) redactHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for key, values := range header {
+		out[key] = strings.Join(values, ", ")
+	}
+	for _, key := range credentialHeaders {
+		if header.Get(key) != "" {
+			out[http.CanonicalHeaderKey(key)] = redactedValue
+		}
+	}
+	for key := range m.Headers {
+		out[http.CanonicalHeaderKey(key)] = redactedValue
+	}
+	return out
+}
+
+// redactBody masks secret values anywhere in a JSON body (e.g. the password
+// inside database details). Non-JSON bodies are returned verbatim.
+func redactBody(body []byte, extraSecrets ...string) string {
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body)
+	}
+	redacted, err := json.Marshal(redactValue(decoded, extraSecrets))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+func redactValue(value any, extraSecrets []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, inner := range v {
+			if isSecretKey(key, extraSecrets) && inner != nil {
+				out[key] = redactedValue
+				continue
+			}
+			out[key] = redactValue(inner, extraSecrets)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, inner := range v {
+			out[i] = redactValue(inner, extraSecrets)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func isSecretKey(key string, extraSecrets []string) bool {
+	lower := strings.ToLower(key)
+	for _, secret := range extraSecrets {
+		if lower == secret {
+			return true
+		}
+	}
+	for _, fragment := range secretKeyFragments {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+type recordingLogger struct{ entries []logEntry }
+
+func (l *recordingLogger) Debug(_ context.Context, msg string, fields map[string]any) {
+	l.entries = append(l.entries, logEntry{"debug", msg, fields})
+}
+
+func (l *recordingLogger) Trace(_ context.Context, msg string, fields map[string]any) {
+	l.entries = append(l.entries, logEntry{"trace", msg, fields})
+}
+
+func (l *recordingLogger) all(level string) []logEntry {
+	var out []logEntry
+	for _, e := range l.entries {
+		if e.level == level {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestLogging_RequestFields(t *testing.T) {
+	transport := &sequenceTransport{replies: []func() (*http.Response, error){status(502), status(200)}}
+	logger := &recordingLogger{}
+	client := newRetryTestClient(transport)
+	client.Logger = logger
+
+	resp, err := client.Get(context.Background(), "/api/database/1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	debug := logger.all("debug")
+	if len(debug) != 2 {
+		t.Fatalf("expected one debug entry per attempt, got %d", len(debug))
+	}
+	for i, want := range []int{502, 200} {
+		f := debug[i].fields
+		if f["method"] != "GET" || f["path"] != "/api/database/1" || f["status"] != want || f["attempt"] != i+1 {
+			t.Errorf("attempt %d: unexpected fields %v", i+1, f)
+		}
+		if _, ok := f["latency_ms"]; !ok {
+			t.Errorf("attempt %d: missing latency_ms", i+1)
+		}
+	}
+}
+
+// quietLogger only emits debug entries.
+type quietLogger struct{ recordingLogger }
+
+func (l *quietLogger) TraceEnabled(context.Context) bool { return false }
+
+func TestLogging_NoBufferingWithoutTrace(t *testing.T) {
+	body := io.NopCloser(strings.NewReader(`{}`))
+	reply := func() (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Header: http.Header{}, Body: body}, nil
+	}
+	transport := &sequenceTransport{replies: []func() (*http.Response, error){reply}}
+	logger := &quietLogger{}
+	client := newRetryTestClient(transport)
+	client.Logger = logger
+
+	resp, err := client.Get(context.Background(), "/api/database/1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(logger.all("debug")) != 1 || len(logger.all("trace")) != 0 {
+		t.Errorf("expected only a debug entry, got %v", logger.entries)
+	}
+	if resp.Body != body {
+		t.Errorf("expected the response body not to be buffered")
+	}
+}
+
+// truncatedBody fails mid-read, like a connection dropped during the body.
+type truncatedBody struct{ read bool }
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.read {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b.read = true
+	return copy(p, `{"id":`), nil
+}
+
+func (b *truncatedBody) Close() error { return nil }
+
+func TestLogging_TruncatedBodyIsRetried(t *testing.T) {
+	truncated := func() (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Header: http.Header{}, Body: &truncatedBody{}}, nil
+	}
+	transport := &sequenceTransport{replies: []func() (*http.Response, error){truncated, status(200)}}
+	logger := &recordingLogger{}
+	client := newRetryTestClient(transport)
+	client.Logger = logger
+
+	resp, err := client.Get(context.Background(), "/api/database/1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if transport.attempts != 2 {
+		t.Errorf("expected the truncated response to be retried, got %d attempts", transport.attempts)
+	}
+	if f := logger.all("debug")[0].fields; !strings.Contains(fmt.Sprint(f["error"]), "unexpected EOF") {
+		t.Errorf("expected the read error to be logged, got %v", f)
+	}
+}
+
+func TestLogging_Redaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/session" {
+			_, _ = w.Write([]byte(`{"id":"session-token-value"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1,"details":{"host":"db","password":"**MetabasePass**"}}`))
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	client := NewMetabaseAPIClient(srv.URL, "",
+		WithSessionCredentials("admin@example.com", "login-password"),
+		WithHeaders(map[string]string{"X-Proxy-Auth": "proxy-secret"}),
+		WithLogger(logger),
+	)
+
+	body := map[string]any{"name": "db", "details": map[string]any{"host": "db", "password": "db-password", "tunnel-private-key": "pk"}}
+	resp, err := client.Put(context.Background(), "/api/database/1", body)
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	resp.Body.Close()
+
+	trace := logger.all("trace")
+	if len(trace) != 2 {
+		t.Fatalf("expected a trace entry for the login and the PUT, got %d", len(trace))
+	}
+
+	dump, _ := json.Marshal(trace[0].fields)
+	dump2, _ := json.Marshal(trace[1].fields)
+	all := string(dump) + string(dump2)
+	for _, secret := range []string{"login-password", "session-token-value", "proxy-secret", "db-password", `"pk"`, "**MetabasePass**"} {
+		if strings.Contains(all, secret) {
+			t.Errorf("secret %q leaked into the logs: %s", secret, all)
+		}
+	}
+	// Non-secret values stay readable.
+	if !strings.Contains(string(dump2), `\"host\":\"db\"`) {
+		t.Errorf("expected non-secret details in the trace, got %s", dump2)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	if got := redactBody([]byte("plain text error")); got != "plain text error" {
+		t.Errorf("expected non-JSON bodies verbatim, got %q", got)
+	}
+	got := redactBody([]byte(`[{"password":"x","name":"a"}]`))
+	if got != `[{"name":"a","password":"**REDACTED**"}]` {
+		t.Errorf("unexpected redaction: %s", got)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dtos
+
+import "encoding/json"
+
+// Type is "question", "model" or "metric". DatasetQuery and
+// VisualizationSettings are kept raw: Terraform manages them as JSON strings.
+type CardDTO struct {
+	Id                    int             `json:"id"`
+	Name                  string          `json:"name"`
+	Description           *string         `json:"description"`
+	CollectionId          *int            `json:"collection_id"`
+	Display               string          `json:"display"`
+	Type                  string          `json:"type"`
+	DatasetQuery          json.RawMessage `json:"dataset_query"`
+	VisualizationSettings json.RawMessage `json:"visualization_settings"`
+	Archived              bool            `json:"archived"`
+}
@@ -4,10 +4,11 @@
 package dtos
 
 // Metabase returns the parent as a path in "location" (e.g. "/", "/12/", "/12/34/"),
-// not as a parent_id field.
+// not as a parent_id field. PersonalOwnerId is set on personal collections.
 type CollectionDTO struct {
-	Id       int    `json:"id"`
-	Name     string `json:"name"`
-	Location string `json:"location"`
-	Archived bool   `json:"archived"`
+	Id              int    `json:"id"`
+	Name            string `json:"name"`
+	Location        string `json:"location"`
+	Archived        bool   `json:"archived"`
+	PersonalOwnerId *int   `json:"personal_owner_id"`
 }
@@ -0,0 +1,14 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dtos
+
+// ConnectionImpersonationDTO is a connection impersonation policy: the
+// group's queries on the database run as the database role named by a user
+// attribute.
+type ConnectionImpersonationDTO struct {
+	Id         int    `json:"id"`
+	GroupId    int    `json:"group_id"`
+	DatabaseId int    `json:"db_id"`
+	Attribute  string `json:"attribute"`
+}
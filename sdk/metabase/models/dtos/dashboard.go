@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dtos
+
+import "encoding/json"
+
+// Parameters is kept raw: Terraform manages it as a JSON string.
+type DashboardDTO struct {
+	Id           int               `json:"id"`
+	Name         string            `json:"name"`
+	Description  *string           `json:"description"`
+	CollectionId *int              `json:"collection_id"`
+	Parameters   json.RawMessage   `json:"parameters"`
+	Tabs         []DashboardTabDTO `json:"tabs"`
+	Dashcards    []DashcardDTO     `json:"dashcards"`
+	Archived     bool              `json:"archived"`
+}
+
+// On update, a negative Id creates a new tab (referenced by the same negative
+// DashboardTabId on its dashcards). Position follows the order of the list.
+type DashboardTabDTO struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// A dashcard places a card on a dashboard grid (24 columns wide). On update, a
+// negative Id creates a new dashcard; existing ids left out are removed.
+type DashcardDTO struct {
+	Id                    int             `json:"id"`
+	CardId                *int            `json:"card_id"`
+	DashboardTabId        *int            `json:"dashboard_tab_id"`
+	Row                   int             `json:"row"`
+	Col                   int             `json:"col"`
+	SizeX                 int             `json:"size_x"`
+	SizeY                 int             `json:"size_y"`
+	ParameterMappings     json.RawMessage `json:"parameter_mappings"`
+	VisualizationSettings json.RawMessage `json:"visualization_settings"`
+}
@@ -8,4 +8,40 @@ type DatabaseDTO struct {
 	Name    string         `json:"name"`
 	Engine  string         `json:"engine"`
 	Details map[string]any `json:"details"`
+	// Schedules are Quartz cron strings; the field-values scan has none when
+	// it is not run on a schedule.
+	MetadataSyncSchedule     string         `json:"metadata_sync_schedule"`
+	CacheFieldValuesSchedule *string        `json:"cache_field_values_schedule"`
+	IsFullSync               bool           `json:"is_full_sync"`
+	IsOnDemand               bool           `json:"is_on_demand"`
+	AutoRunQueries           bool           `json:"auto_run_queries"`
+	Refingerprint            *bool          `json:"refingerprint"`
+	CacheTTL                 *int           `json:"cache_ttl"`
+	Settings                 map[string]any `json:"settings"`
+	// InitialSyncStatus is "incomplete" until the first sync after create
+	// finishes, then "complete" (or "aborted" when it failed).
+	InitialSyncStatus string `json:"initial_sync_status"`
+}
+
+// DatabaseOptionsDTO is the sync, scan and query options of a database. Nil
+// fields are not managed: writes omit them and Metabase leaves them untouched.
+type DatabaseOptionsDTO struct {
+	// MetadataSyncSchedule and CacheFieldValuesSchedule are cron strings (see
+	// metabase.ParseCronSchedule).
+	MetadataSyncSchedule     *string
+	CacheFieldValuesSchedule *string
+	IsFullSync               *bool
+	IsOnDemand               *bool
+	AutoRunQueries           *bool
+	Refingerprint            *bool
+	CacheTTL                 *int
+	// Settings are database-local settings; a nil value unsets the key.
+	Settings map[string]any
+}
+
+// ConnectionValidationDTO is the result of testing connection details.
+type ConnectionValidationDTO struct {
+	Valid bool `json:"valid"`
+	// Message is the driver's error when the connection failed.
+	Message string `json:"message"`
 }
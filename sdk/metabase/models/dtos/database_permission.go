@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dtos
+
+// DatabasePermissionDTO is one group/database edge of the data permissions
+// graph. Nil (or empty) fields are not managed: writes omit them and Metabase
+// leaves them untouched. Granular values the flat fields can't express are
+// read back as their JSON encoding.
+type DatabasePermissionDTO struct {
+	// CreateQueries is "no", "query-builder" or "query-builder-and-native", or
+	// "granular" when CreateQueriesSchemas is set.
+	CreateQueries string
+	// CreateQueriesSchemas is the create-queries level per schema name.
+	CreateQueriesSchemas map[string]SchemaPermissionDTO
+	// ViewData is "unrestricted", "blocked", "impersonated" or "sandboxed".
+	ViewData *string
+	// Download is the database-wide level: "none", "limited" or "full".
+	Download *string
+	// DownloadSchemas is the download level per schema name.
+	DownloadSchemas map[string]string
+	// DataModel is "all" or "none".
+	DataModel *string
+	// Details is "yes" or "no".
+	Details *string
+}
+
+// SchemaPermissionDTO is a permission on one schema: Level for the whole
+// schema, or Tables (table id → level) when it is granular.
+type SchemaPermissionDTO struct {
+	Level  string
+	Tables map[string]string
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dtos
+
+type FieldDTO struct {
+	Id              int     `json:"id"`
+	TableId         int     `json:"table_id"`
+	Name            string  `json:"name"`
+	DisplayName     string  `json:"display_name"`
+	Description     *string `json:"description"`
+	SemanticType    *string `json:"semantic_type"`
+	VisibilityType  string  `json:"visibility_type"`
+	FkTargetFieldId *int    `json:"fk_target_field_id"`
+	// ParentId is set on the nested fields of JSON columns.
+	ParentId *int `json:"parent_id"`
+	Active   bool `json:"active"`
+}
+
+// FieldMetadataDTO is the curated metadata written by PUT /api/field/:id. Nil
+// fields are left untouched; "" clears Description, SemanticType and
+// FkTargetFieldId.
+type FieldMetadataDTO struct {
+	DisplayName     *string
+	Description     *string
+	SemanticType    *string
+	VisibilityType  *string
+	FkTargetFieldId *string
+}
@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dtos
+
+// SandboxDTO is a data sandbox (row-level security policy, "GTAP" in the API):
+// the rows of a table a group sees, filtered by a card and/or by user
+// attributes.
+type SandboxDTO struct {
+	Id      int
+	GroupId int
+	TableId int
+	// CardId is the saved question the table is replaced with; nil filters the
+	// table itself.
+	CardId *int
+	// AttributeRemappings maps a user attribute to the column or template tag
+	// it filters.
+	AttributeRemappings map[string]SandboxTargetDTO
+}
+
+// SandboxTargetDTO is what a user attribute filters: a field (FieldId) or a
+// variable of a native card (TemplateTag). Both are nil for a target the
+// provider can't express.
+type SandboxTargetDTO struct {
+	FieldId     *int
+	TemplateTag *string
+}
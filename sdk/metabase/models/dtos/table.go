@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dtos
+
+type TableDTO struct {
+	Id             int     `json:"id"`
+	DbId           int     `json:"db_id"`
+	Schema         *string `json:"schema"`
+	Name           string  `json:"name"`
+	DisplayName    string  `json:"display_name"`
+	Description    *string `json:"description"`
+	VisibilityType *string `json:"visibility_type"`
+	EntityType     *string `json:"entity_type"`
+	// Active is false once the table is gone from the database (kept by
+	// Metabase after a sync).
+	Active bool `json:"active"`
+}
+
+// TableMetadataDTO is the curated metadata written by PUT /api/table/:id. Nil
+// fields are left untouched; "" clears Description and makes the table
+// visible (VisibilityType).
+type TableMetadataDTO struct {
+	DisplayName    *string
+	Description    *string
+	VisibilityType *string
+	EntityType     *string
+}
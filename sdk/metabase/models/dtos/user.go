@@ -4,9 +4,28 @@
 package dtos
 
 type UserDTO struct {
-	Id        int    `json:"id"`
-	Email     string `json:"email"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	IsActive  bool   `json:"is_active"`
+	Id          int    `json:"id"`
+	Email       string `json:"email"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	IsActive    bool   `json:"is_active"`
+	IsSuperuser bool   `json:"is_superuser"`
+	// LoginAttributes are the user attributes sandboxes and connection
+	// impersonation filter on; values are usually strings.
+	LoginAttributes map[string]any `json:"login_attributes"`
+	// Locale is nil for the instance's default language.
+	Locale *string `json:"locale"`
+	// SsoSource is how the user signs in through SSO ("google", "ldap",
+	// "saml", "jwt"), nil for a password login.
+	SsoSource *string `json:"sso_source"`
+}
+
+// UserOptionsDTO is the access and profile settings of a user. Nil fields are
+// not managed: writes omit them and Metabase leaves them untouched.
+type UserOptionsDTO struct {
+	IsSuperuser *bool
+	// LoginAttributes replaces every attribute; empty clears them.
+	LoginAttributes map[string]string
+	// Locale is "" for the instance's default language.
+	Locale *string
 }
@@ -4,7 +4,8 @@
 package dtos
 
 type UserPermissionGroupMembershipDTO struct {
-	MembershipId int `json:"membership_id"`
-	UserId       int `json:"user_id"`
-	GroupId      int `json:"group_id"`
+	MembershipId   int  `json:"membership_id"`
+	UserId         int  `json:"user_id"`
+	GroupId        int  `json:"group_id"`
+	IsGroupManager bool `json:"is_group_manager"`
 }
@@ -0,0 +1,14 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ApplicationPermissionTerraformModel struct {
+	Id      types.String `tfsdk:"id"`
+	GroupId types.String `tfsdk:"group_id"`
+	Area    types.String `tfsdk:"area"`
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type CardTerraformModel struct {
+	Id                    types.String `tfsdk:"id"`
+	Name                  types.String `tfsdk:"name"`
+	Description           types.String `tfsdk:"description"`
+	CollectionId          types.String `tfsdk:"collection_id"`
+	Display               types.String `tfsdk:"display"`
+	Type                  types.String `tfsdk:"type"`
+	DatasetQuery          JSONValue    `tfsdk:"dataset_query"`
+	VisualizationSettings JSONValue    `tfsdk:"visualization_settings"`
+	Archived              types.Bool   `tfsdk:"archived"`
+}
+
+// CreateCardTerraformModelFromDTO reconciles the JSON attributes against
+// existing (plan or state) so Metabase's normalization is not reported as drift.
+func CreateCardTerraformModelFromDTO(source *dtos.CardDTO, existing CardTerraformModel) (CardTerraformModel, error) {
+	datasetQuery, err := ReconcileJSON(source.DatasetQuery, existing.DatasetQuery.ValueString())
+	if err != nil {
+		return CardTerraformModel{}, err
+	}
+	visualizationSettings, err := ReconcileJSON(source.VisualizationSettings, existing.VisualizationSettings.ValueString())
+	if err != nil {
+		return CardTerraformModel{}, err
+	}
+
+	collectionId := types.StringNull()
+	if source.CollectionId != nil {
+		collectionId = types.StringValue(strconv.Itoa(*source.CollectionId))
+	}
+
+	return CardTerraformModel{
+		Id:                    types.StringValue(strconv.Itoa(source.Id)),
+		Name:                  types.StringValue(source.Name),
+		Description:           types.StringPointerValue(source.Description),
+		CollectionId:          collectionId,
+		Display:               types.StringValue(source.Display),
+		Type:                  types.StringValue(source.Type),
+		DatasetQuery:          NewJSONValue(datasetQuery),
+		VisualizationSettings: NewJSONValue(visualizationSettings),
+		Archived:              types.BoolValue(source.Archived),
+	}, nil
+}
@@ -36,3 +36,10 @@ func parentIdFromLocation(location string) types.String {
 	parts := strings.Split(trimmed, "/")
 	return types.StringValue(parts[len(parts)-1])
 }
+
+type CollectionsDataSourceTerraformModel struct {
+	ParentId        types.String               `tfsdk:"parent_id"`
+	NameRegex       types.String               `tfsdk:"name_regex"`
+	IncludePersonal types.Bool                 `tfsdk:"include_personal"`
+	Collections     []CollectionTerraformModel `tfsdk:"collections"`
+}
@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ConnectionImpersonationTerraformModel struct {
+	Id               types.String `tfsdk:"id"`
+	GroupId          types.String `tfsdk:"group_id"`
+	DatabaseId       types.String `tfsdk:"database_id"`
+	Attribute        types.String `tfsdk:"attribute"`
+	PreviousViewData types.String `tfsdk:"previous_view_data"`
+}
+
+// CreateConnectionImpersonationTerraformModelFromDTO builds the model from the
+// policy. previous_view_data is kept from existing, "blocked" when unknown
+// (e.g. on import).
+func CreateConnectionImpersonationTerraformModelFromDTO(source *dtos.ConnectionImpersonationDTO, existing ConnectionImpersonationTerraformModel) ConnectionImpersonationTerraformModel {
+	groupId, databaseId := strconv.Itoa(source.GroupId), strconv.Itoa(source.DatabaseId)
+	result := ConnectionImpersonationTerraformModel{
+		Id:               types.StringValue(groupId + ":" + databaseId),
+		GroupId:          types.StringValue(groupId),
+		DatabaseId:       types.StringValue(databaseId),
+		Attribute:        types.StringValue(source.Attribute),
+		PreviousViewData: existing.PreviousViewData,
+	}
+	if existing.PreviousViewData.IsNull() || existing.PreviousViewData.IsUnknown() {
+		result.PreviousViewData = types.StringValue("blocked")
+	}
+	return result
+}
@@ -0,0 +1,341 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type DashboardTerraformModel struct {
+	Id           types.String                 `tfsdk:"id"`
+	Name         types.String                 `tfsdk:"name"`
+	Description  types.String                 `tfsdk:"description"`
+	CollectionId types.String                 `tfsdk:"collection_id"`
+	Parameters   JSONValue                    `tfsdk:"parameters"`
+	Tabs         []DashboardTabTerraformModel `tfsdk:"tabs"`
+	Dashcards    []DashcardTerraformModel     `tfsdk:"dashcards"`
+}
+
+type DashboardTabTerraformModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+type DashcardTerraformModel struct {
+	CardId                types.String `tfsdk:"card_id"`
+	Tab                   types.String `tfsdk:"tab"`
+	Row                   types.Int64  `tfsdk:"row"`
+	Col                   types.Int64  `tfsdk:"col"`
+	SizeX                 types.Int64  `tfsdk:"size_x"`
+	SizeY                 types.Int64  `tfsdk:"size_y"`
+	ParameterMappings     JSONValue    `tfsdk:"parameter_mappings"`
+	VisualizationSettings JSONValue    `tfsdk:"visualization_settings"`
+}
+
+// dashcardKey is a dashcard's identity: the card it shows and the tab it is on.
+// Layout (row, col, size) is not part of it, so moving a card keeps its dashcard.
+type dashcardKey struct {
+	tab    string
+	cardId string
+}
+
+func (d DashcardTerraformModel) key() dashcardKey {
+	return dashcardKey{tab: d.Tab.ValueString(), cardId: d.CardId.ValueString()}
+}
+
+func (d DashcardTerraformModel) sameLayout(dto dtos.DashcardDTO) bool {
+	return d.Row.ValueInt64() == int64(dto.Row) && d.Col.ValueInt64() == int64(dto.Col) &&
+		d.SizeX.ValueInt64() == int64(dto.SizeX) && d.SizeY.ValueInt64() == int64(dto.SizeY)
+}
+
+func dtoDashcardKey(dto dtos.DashcardDTO, tabNames map[int]string) dashcardKey {
+	key := dashcardKey{}
+	if dto.DashboardTabId != nil {
+		key.tab = tabNames[*dto.DashboardTabId]
+	}
+	if dto.CardId != nil {
+		key.cardId = strconv.Itoa(*dto.CardId)
+	}
+	return key
+}
+
+// matchDashcards pairs each wanted dashcard with an existing one of the same
+// identity, returning an index into have (or -1) for each element of want. A
+// card placed twice on the same tab is disambiguated by layout first, then by
+// id (creation order), so re-ordering or moving cards never re-creates them.
+func matchDashcards(want []DashcardTerraformModel, have []dtos.DashcardDTO, tabNames map[int]string) []int {
+	byKey := map[dashcardKey][]int{}
+	for i, dto := range have {
+		k := dtoDashcardKey(dto, tabNames)
+		byKey[k] = append(byKey[k], i)
+	}
+	for _, candidates := range byKey {
+		sort.Slice(candidates, func(a, b int) bool { return have[candidates[a]].Id < have[candidates[b]].Id })
+	}
+
+	matches := make([]int, len(want))
+	used := make([]bool, len(have))
+	for i := range matches {
+		matches[i] = -1
+	}
+	// Exact layout first, so a duplicated card that did not move keeps its dashcard.
+	for i, d := range want {
+		for _, j := range byKey[d.key()] {
+			if !used[j] && d.sameLayout(have[j]) {
+				matches[i], used[j] = j, true
+				break
+			}
+		}
+	}
+	for i, d := range want {
+		if matches[i] != -1 {
+			continue
+		}
+		for _, j := range byKey[d.key()] {
+			if !used[j] {
+				matches[i], used[j] = j, true
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// BuildDashboardLayout returns the tabs and dashcards to PUT for the planned
+// layout. Tabs are matched by name and dashcards by identity (see
+// matchDashcards) against current, reusing their ids; anything new gets a
+// negative id, and anything in current left out is removed by Metabase.
+func BuildDashboardLayout(plan DashboardTerraformModel, current *dtos.DashboardDTO) ([]dtos.DashboardTabDTO, []dtos.DashcardDTO, error) {
+	currentTabIds := map[string]int{}
+	currentTabNames := map[int]string{}
+	for _, tab := range current.Tabs {
+		currentTabIds[tab.Name] = tab.Id
+		currentTabNames[tab.Id] = tab.Name
+	}
+
+	nextId := -1
+	tabs := make([]dtos.DashboardTabDTO, 0, len(plan.Tabs))
+	tabIds := map[string]int{}
+	for i, tab := range plan.Tabs {
+		name := tab.Name.ValueString()
+		if _, ok := tabIds[name]; ok {
+			return nil, nil, fmt.Errorf("duplicate tab name %q: tab names must be unique", name)
+		}
+		id, ok := currentTabIds[name]
+		if !ok {
+			id = nextId
+			nextId--
+		}
+		tabIds[name] = id
+		tabs = append(tabs, dtos.DashboardTabDTO{Id: id, Name: name, Position: i})
+	}
+
+	matches := matchDashcards(plan.Dashcards, current.Dashcards, currentTabNames)
+	nextId = -1
+	dashcards := make([]dtos.DashcardDTO, 0, len(plan.Dashcards))
+	for i, d := range plan.Dashcards {
+		cardId, err := strconv.Atoi(d.CardId.ValueString())
+		if err != nil {
+			return nil, nil, fmt.Errorf("dashcards[%d]: invalid card_id %q: must be a numeric card ID", i, d.CardId.ValueString())
+		}
+
+		var tabId *int
+		switch {
+		case len(plan.Tabs) == 0 && !d.Tab.IsNull():
+			return nil, nil, fmt.Errorf("dashcards[%d]: tab %q is set but the dashboard declares no tabs", i, d.Tab.ValueString())
+		case len(plan.Tabs) > 0 && d.Tab.IsNull():
+			return nil, nil, fmt.Errorf("dashcards[%d]: tab is required when the dashboard declares tabs", i)
+		case len(plan.Tabs) > 0:
+			id, ok := tabIds[d.Tab.ValueString()]
+			if !ok {
+				return nil, nil, fmt.Errorf("dashcards[%d]: tab %q is not declared in tabs", i, d.Tab.ValueString())
+			}
+			tabId = &id
+		}
+
+		id := nextId
+		if matches[i] != -1 {
+			id = current.Dashcards[matches[i]].Id
+		} else {
+			nextId--
+		}
+
+		dashcards = append(dashcards, dtos.DashcardDTO{
+			Id:                    id,
+			CardId:                &cardId,
+			DashboardTabId:        tabId,
+			Row:                   int(d.Row.ValueInt64()),
+			Col:                   int(d.Col.ValueInt64()),
+			SizeX:                 int(d.SizeX.ValueInt64()),
+			SizeY:                 int(d.SizeY.ValueInt64()),
+			ParameterMappings:     jsonOrDefault(d.ParameterMappings, "[]"),
+			VisualizationSettings: jsonOrDefault(d.VisualizationSettings, "{}"),
+		})
+	}
+	return tabs, dashcards, nil
+}
+
+// CreateDashboardTerraformModelFromDTO keeps the order of existing (plan or
+// state): tabs by name and dashcards by identity. Tabs and dashcards only in
+// Metabase are appended (in grid order), so they show up as drift.
+func CreateDashboardTerraformModelFromDTO(source *dtos.DashboardDTO, existing DashboardTerraformModel) (DashboardTerraformModel, error) {
+	parameters, err := reconcileOptionalJSON(source.Parameters, existing.Parameters)
+	if err != nil {
+		return DashboardTerraformModel{}, fmt.Errorf("parameters: %w", err)
+	}
+
+	sourceTabs := append([]dtos.DashboardTabDTO(nil), source.Tabs...)
+	sort.SliceStable(sourceTabs, func(a, b int) bool { return sourceTabs[a].Position < sourceTabs[b].Position })
+	tabNames := map[int]string{}
+	tabPositions := map[int]int{}
+	sourceTabIds := map[string]bool{}
+	for _, tab := range sourceTabs {
+		tabNames[tab.Id] = tab.Name
+		tabPositions[tab.Id] = tab.Position
+		sourceTabIds[tab.Name] = true
+	}
+
+	var tabs []DashboardTabTerraformModel
+	if existing.Tabs != nil || len(sourceTabs) > 0 {
+		tabs = []DashboardTabTerraformModel{}
+	}
+	seen := map[string]bool{}
+	for _, tab := range existing.Tabs {
+		name := tab.Name.ValueString()
+		if sourceTabIds[name] && !seen[name] {
+			tabs = append(tabs, DashboardTabTerraformModel{Name: types.StringValue(name)})
+			seen[name] = true
+		}
+	}
+	for _, tab := range sourceTabs {
+		if !seen[tab.Name] {
+			tabs = append(tabs, DashboardTabTerraformModel{Name: types.StringValue(tab.Name)})
+			seen[tab.Name] = true
+		}
+	}
+
+	var dashcards []DashcardTerraformModel
+	if existing.Dashcards != nil || len(source.Dashcards) > 0 {
+		dashcards = []DashcardTerraformModel{}
+	}
+	matches := matchDashcards(existing.Dashcards, source.Dashcards, tabNames)
+	used := make([]bool, len(source.Dashcards))
+	for i, j := range matches {
+		if j == -1 {
+			continue
+		}
+		d, err := dashcardFromDTO(source.Dashcards[j], tabNames, existing.Dashcards[i])
+		if err != nil {
+			return DashboardTerraformModel{}, err
+		}
+		dashcards = append(dashcards, d)
+		used[j] = true
+	}
+
+	var extra []dtos.DashcardDTO
+	for j, dto := range source.Dashcards {
+		if !used[j] {
+			extra = append(extra, dto)
+		}
+	}
+	tabPosition := func(d dtos.DashcardDTO) int {
+		if d.DashboardTabId == nil {
+			return 0
+		}
+		return tabPositions[*d.DashboardTabId]
+	}
+	sort.SliceStable(extra, func(a, b int) bool {
+		if pa, pb := tabPosition(extra[a]), tabPosition(extra[b]); pa != pb {
+			return pa < pb
+		}
+		if extra[a].Row != extra[b].Row {
+			return extra[a].Row < extra[b].Row
+		}
+		return extra[a].Col < extra[b].Col
+	})
+	for _, dto := range extra {
+		d, err := dashcardFromDTO(dto, tabNames, DashcardTerraformModel{})
+		if err != nil {
+			return DashboardTerraformModel{}, err
+		}
+		dashcards = append(dashcards, d)
+	}
+
+	collectionId := types.StringNull()
+	if source.CollectionId != nil {
+		collectionId = types.StringValue(strconv.Itoa(*source.CollectionId))
+	}
+
+	return DashboardTerraformModel{
+		Id:           types.StringValue(strconv.Itoa(source.Id)),
+		Name:         types.StringValue(source.Name),
+		Description:  types.StringPointerValue(source.Description),
+		CollectionId: collectionId,
+		Parameters:   parameters,
+		Tabs:         tabs,
+		Dashcards:    dashcards,
+	}, nil
+}
+
+func dashcardFromDTO(source dtos.DashcardDTO, tabNames map[int]string, existing DashcardTerraformModel) (DashcardTerraformModel, error) {
+	parameterMappings, err := reconcileOptionalJSON(source.ParameterMappings, existing.ParameterMappings)
+	if err != nil {
+		return DashcardTerraformModel{}, fmt.Errorf("dashcard %d parameter_mappings: %w", source.Id, err)
+	}
+	visualizationSettings, err := reconcileOptionalJSON(source.VisualizationSettings, existing.VisualizationSettings)
+	if err != nil {
+		return DashcardTerraformModel{}, fmt.Errorf("dashcard %d visualization_settings: %w", source.Id, err)
+	}
+
+	key := dtoDashcardKey(source, tabNames)
+	cardId, tab := types.StringNull(), types.StringNull()
+	if source.CardId != nil {
+		cardId = types.StringValue(key.cardId)
+	}
+	if source.DashboardTabId != nil {
+		tab = types.StringValue(key.tab)
+	}
+
+	return DashcardTerraformModel{
+		CardId:                cardId,
+		Tab:                   tab,
+		Row:                   types.Int64Value(int64(source.Row)),
+		Col:                   types.Int64Value(int64(source.Col)),
+		SizeX:                 types.Int64Value(int64(source.SizeX)),
+		SizeY:                 types.Int64Value(int64(source.SizeY)),
+		ParameterMappings:     parameterMappings,
+		VisualizationSettings: visualizationSettings,
+	}, nil
+}
+
+// reconcileOptionalJSON is ReconcileJSON for an optional attribute: when it is
+// unset, Metabase's empty default ([] or {}) keeps it null.
+func reconcileOptionalJSON(apiJSON json.RawMessage, existing JSONValue) (JSONValue, error) {
+	if existing.IsNull() || existing.IsUnknown() {
+		switch string(bytes.TrimSpace(apiJSON)) {
+		case "", "null", "[]", "{}":
+			return NewJSONNull(), nil
+		}
+		existing = NewJSONNull()
+	}
+	reconciled, err := ReconcileJSON(apiJSON, existing.ValueString())
+	if err != nil {
+		return NewJSONNull(), err
+	}
+	return NewJSONValue(reconciled), nil
+}
+
+// jsonOrDefault returns the attribute's JSON, or def when it is unset.
+func jsonOrDefault(value JSONValue, def string) json.RawMessage {
+	if value.IsNull() || value.IsUnknown() {
+		return json.RawMessage(def)
+	}
+	return json.RawMessage(value.ValueString())
+}
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func intPtr(i int) *int { return &i }
+
+func dashcard(cardId, tab string, row, col int64) DashcardTerraformModel {
+	d := DashcardTerraformModel{
+		CardId:                types.StringValue(cardId),
+		Tab:                   types.StringNull(),
+		Row:                   types.Int64Value(row),
+		Col:                   types.Int64Value(col),
+		SizeX:                 types.Int64Value(6),
+		SizeY:                 types.Int64Value(4),
+		ParameterMappings:     NewJSONNull(),
+		VisualizationSettings: NewJSONNull(),
+	}
+	if tab != "" {
+		d.Tab = types.StringValue(tab)
+	}
+	return d
+}
+
+func dashcardDTO(id, cardId int, tabId *int, row, col int) dtos.DashcardDTO {
+	return dtos.DashcardDTO{
+		Id: id, CardId: intPtr(cardId), DashboardTabId: tabId, Row: row, Col: col, SizeX: 6, SizeY: 4,
+		ParameterMappings: json.RawMessage("[]"), VisualizationSettings: json.RawMessage("{}"),
+	}
+}
+
+func TestBuildDashboardLayout(t *testing.T) {
+	t.Run("re-ordered and moved dashcards keep their ids", func(t *testing.T) {
+		current := &dtos.DashboardDTO{Dashcards: []dtos.DashcardDTO{
+			dashcardDTO(10, 1, nil, 0, 0),
+			dashcardDTO(11, 2, nil, 0, 6),
+		}}
+		plan := DashboardTerraformModel{Dashcards: []DashcardTerraformModel{
+			dashcard("2", "", 4, 0),
+			dashcard("1", "", 0, 0),
+			dashcard("3", "", 8, 0),
+		}}
+
+		_, dashcards, err := BuildDashboardLayout(plan, current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []int{dashcards[0].Id, dashcards[1].Id, dashcards[2].Id}
+		if got[0] != 11 || got[1] != 10 || got[2] != -1 {
+			t.Fatalf("expected ids [11 10 -1], got %v", got)
+		}
+		if dashcards[0].Row != 4 {
+			t.Fatalf("expected the moved dashcard at row 4, got %d", dashcards[0].Row)
+		}
+	})
+
+	t.Run("a duplicated card is matched by layout first", func(t *testing.T) {
+		current := &dtos.DashboardDTO{Dashcards: []dtos.DashcardDTO{
+			dashcardDTO(10, 1, nil, 0, 0),
+			dashcardDTO(11, 1, nil, 4, 0),
+		}}
+		// Only the second one stays, unmoved: it must keep id 11, not take 10.
+		plan := DashboardTerraformModel{Dashcards: []DashcardTerraformModel{dashcard("1", "", 4, 0)}}
+
+		_, dashcards, err := BuildDashboardLayout(plan, current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dashcards[0].Id != 11 {
+			t.Fatalf("expected id 11, got %d", dashcards[0].Id)
+		}
+	})
+
+	t.Run("tabs are matched by name and new ones get negative ids", func(t *testing.T) {
+		current := &dtos.DashboardDTO{
+			Tabs:      []dtos.DashboardTabDTO{{Id: 5, Name: "Overview", Position: 0}},
+			Dashcards: []dtos.DashcardDTO{dashcardDTO(10, 1, intPtr(5), 0, 0)},
+		}
+		plan := DashboardTerraformModel{
+			Tabs: []DashboardTabTerraformModel{{Name: types.StringValue("Details")}, {Name: types.StringValue("Overview")}},
+			Dashcards: []DashcardTerraformModel{
+				dashcard("1", "Overview", 0, 0),
+				dashcard("1", "Details", 0, 0),
+			},
+		}
+
+		tabs, dashcards, err := BuildDashboardLayout(plan, current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tabs[0].Id != -1 || tabs[1].Id != 5 || tabs[1].Position != 1 {
+			t.Fatalf("unexpected tabs %+v", tabs)
+		}
+		if dashcards[0].Id != 10 || *dashcards[0].DashboardTabId != 5 {
+			t.Fatalf("expected the Overview dashcard to keep id 10, got %+v", dashcards[0])
+		}
+		if dashcards[1].Id != -1 || *dashcards[1].DashboardTabId != -1 {
+			t.Fatalf("expected a new dashcard on the new tab, got %+v", dashcards[1])
+		}
+	})
+
+	t.Run("invalid tab references are rejected", func(t *testing.T) {
+		empty := &dtos.DashboardDTO{}
+		tabs := []DashboardTabTerraformModel{{Name: types.StringValue("Overview")}}
+		cases := map[string]DashboardTerraformModel{
+			"missing tab":    {Tabs: tabs, Dashcards: []DashcardTerraformModel{dashcard("1", "", 0, 0)}},
+			"undeclared tab": {Tabs: tabs, Dashcards: []DashcardTerraformModel{dashcard("1", "Other", 0, 0)}},
+			"no tabs":        {Dashcards: []DashcardTerraformModel{dashcard("1", "Overview", 0, 0)}},
+			"duplicate tab":  {Tabs: append(tabs, tabs...)},
+		}
+		for name, plan := range cases {
+			if _, _, err := BuildDashboardLayout(plan, empty); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
+
+func TestCreateDashboardTerraformModelFromDTO(t *testing.T) {
+	source := &dtos.DashboardDTO{
+		Id:         7,
+		Name:       "Sales",
+		Parameters: json.RawMessage("[]"),
+		Tabs: []dtos.DashboardTabDTO{
+			{Id: 2, Name: "Details", Position: 1},
+			{Id: 1, Name: "Overview", Position: 0},
+		},
+		Dashcards: []dtos.DashcardDTO{
+			dashcardDTO(10, 1, intPtr(1), 0, 0),
+			dashcardDTO(11, 2, intPtr(2), 0, 0),
+			dashcardDTO(12, 3, intPtr(1), 4, 0),
+		},
+	}
+
+	t.Run("keeps the order of state and appends what is only in Metabase", func(t *testing.T) {
+		existing := DashboardTerraformModel{
+			Parameters: NewJSONNull(),
+			Tabs:       []DashboardTabTerraformModel{{Name: types.StringValue("Overview")}, {Name: types.StringValue("Details")}},
+			Dashcards: []DashcardTerraformModel{
+				dashcard("2", "Details", 0, 0),
+				dashcard("1", "Overview", 0, 0),
+			},
+		}
+
+		got, err := CreateDashboardTerraformModelFromDTO(source, existing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Parameters.IsNull() {
+			t.Fatalf("expected empty parameters to stay null, got %s", got.Parameters.ValueString())
+		}
+		if len(got.Dashcards) != 3 {
+			t.Fatalf("expected 3 dashcards, got %d", len(got.Dashcards))
+		}
+		order := []string{got.Dashcards[0].CardId.ValueString(), got.Dashcards[1].CardId.ValueString(), got.Dashcards[2].CardId.ValueString()}
+		if order[0] != "2" || order[1] != "1" || order[2] != "3" {
+			t.Fatalf("expected card order [2 1 3], got %v", order)
+		}
+		if got.Dashcards[2].Tab.ValueString() != "Overview" {
+			t.Fatalf("expected the extra dashcard on Overview, got %s", got.Dashcards[2].Tab.ValueString())
+		}
+		if !got.Dashcards[0].ParameterMappings.IsNull() || !got.Dashcards[0].VisualizationSettings.IsNull() {
+			t.Fatal("expected empty JSON defaults to stay null")
+		}
+	})
+
+	t.Run("import orders tabs by position and dashcards by grid", func(t *testing.T) {
+		got, err := CreateDashboardTerraformModelFromDTO(source, DashboardTerraformModel{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Tabs[0].Name.ValueString() != "Overview" || got.Tabs[1].Name.ValueString() != "Details" {
+			t.Fatalf("expected tabs [Overview Details], got %+v", got.Tabs)
+		}
+		order := []string{got.Dashcards[0].CardId.ValueString(), got.Dashcards[1].CardId.ValueString(), got.Dashcards[2].CardId.ValueString()}
+		if order[0] != "1" || order[1] != "3" || order[2] != "2" {
+			t.Fatalf("expected card order [1 3 2], got %v", order)
+		}
+	})
+}
@@ -9,30 +9,136 @@ import (
 	"reflect"
 	"strconv"
 
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type DatabaseTerraformModel struct {
-	Id                 types.String `tfsdk:"id"`
-	Name               types.String `tfsdk:"name"`
-	Engine             types.String `tfsdk:"engine"`
-	Details            types.String `tfsdk:"details"`
+	Id      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Engine  types.String `tfsdk:"engine"`
+	Details types.String `tfsdk:"details"`
+	// DetailsWO is write-only: always null outside the config.
+	DetailsWO          types.String `tfsdk:"details_wo"`
+	DetailsWOVersion   types.Int64  `tfsdk:"details_wo_version"`
 	RedactedAttributes types.Set    `tfsdk:"redacted_attributes"`
+	// Typed connection blocks, an alternative to Details (at most one is set).
+	Postgres           types.Object `tfsdk:"postgres"`
+	Mysql              types.Object `tfsdk:"mysql"`
+	Redshift           types.Object `tfsdk:"redshift"`
+	Snowflake          types.Object `tfsdk:"snowflake"`
+	BigqueryCloudSdk   types.Object `tfsdk:"bigquery_cloud_sdk"`
+	H2                 types.Object `tfsdk:"h2"`
 	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+	WaitForSync        types.Bool   `tfsdk:"wait_for_sync"`
+	WaitForSyncTimeout types.String `tfsdk:"wait_for_sync_timeout"`
+	ValidateConnection types.Bool   `tfsdk:"validate_connection"`
+	SyncOnChange       types.Bool   `tfsdk:"sync_on_change"`
+	Schedules          types.Object `tfsdk:"schedules"`
+	IsFullSync         types.Bool   `tfsdk:"is_full_sync"`
+	IsOnDemand         types.Bool   `tfsdk:"is_on_demand"`
+	AutoRunQueries     types.Bool   `tfsdk:"auto_run_queries"`
+	Refingerprint      types.Bool   `tfsdk:"refingerprint"`
+	CacheTTL           types.Int64  `tfsdk:"cache_ttl"`
+	Settings           JSONValue    `tfsdk:"settings"`
 }
 
-// details/redactedAttributes/deletionProtection are carried from plan/state, not
-// the DTO (secrets are redacted server-side; the latter two are Terraform-only).
-func CreateDatabaseTerraformModelFromDTO(source *dtos.DatabaseDTO, details types.String, redactedAttributes types.Set, deletionProtection types.Bool) DatabaseTerraformModel {
+// DatabaseSchedulesTerraformModel is the schedules attribute: cron strings of
+// the metadata sync and the field-values scan.
+type DatabaseSchedulesTerraformModel struct {
+	MetadataSync     types.String `tfsdk:"metadata_sync"`
+	CacheFieldValues types.String `tfsdk:"cache_field_values"`
+}
+
+var DatabaseSchedulesType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"metadata_sync":      types.StringType,
+	"cache_field_values": types.StringType,
+}}
+
+// details, details_wo_version, the typed connection blocks,
+// redacted_attributes and the Terraform-only attributes
+// (deletion_protection, wait_for_sync…) are carried from existing (plan or
+// state), not the DTO: secrets are redacted server-side. Schedules equivalent to the existing cron strings and
+// settings containing the existing ones keep the existing value; settings stay
+// null when existing does not manage them.
+func CreateDatabaseTerraformModelFromDTO(source *dtos.DatabaseDTO, existing DatabaseTerraformModel) (DatabaseTerraformModel, error) {
+	settings := NewJSONNull()
+	if !existing.Settings.IsNull() && !existing.Settings.IsUnknown() {
+		apiSettings := json.RawMessage("{}")
+		if source.Settings != nil {
+			b, err := json.Marshal(source.Settings)
+			if err != nil {
+				return DatabaseTerraformModel{}, err
+			}
+			apiSettings = b
+		}
+		reconciled, err := ReconcileJSON(apiSettings, existing.Settings.ValueString())
+		if err != nil {
+			return DatabaseTerraformModel{}, err
+		}
+		settings = NewJSONValue(reconciled)
+	}
+
+	cacheTTL := types.Int64Null()
+	if source.CacheTTL != nil {
+		cacheTTL = types.Int64Value(int64(*source.CacheTTL))
+	}
+
 	return DatabaseTerraformModel{
 		Id:                 types.StringValue(strconv.Itoa(source.Id)),
 		Name:               types.StringValue(source.Name),
 		Engine:             types.StringValue(source.Engine),
-		Details:            details,
-		RedactedAttributes: redactedAttributes,
-		DeletionProtection: deletionProtection,
+		Details:            existing.Details,
+		DetailsWO:          types.StringNull(),
+		DetailsWOVersion:   existing.DetailsWOVersion,
+		RedactedAttributes: existing.RedactedAttributes,
+		Postgres:           existing.Postgres,
+		Mysql:              existing.Mysql,
+		Redshift:           existing.Redshift,
+		Snowflake:          existing.Snowflake,
+		BigqueryCloudSdk:   existing.BigqueryCloudSdk,
+		H2:                 existing.H2,
+		DeletionProtection: existing.DeletionProtection,
+		WaitForSync:        existing.WaitForSync,
+		WaitForSyncTimeout: existing.WaitForSyncTimeout,
+		ValidateConnection: existing.ValidateConnection,
+		SyncOnChange:       existing.SyncOnChange,
+		Schedules: types.ObjectValueMust(DatabaseSchedulesType.AttrTypes, map[string]attr.Value{
+			"metadata_sync":      reconcileSchedule(&source.MetadataSyncSchedule, existingSchedule(existing.Schedules, "metadata_sync")),
+			"cache_field_values": reconcileSchedule(source.CacheFieldValuesSchedule, existingSchedule(existing.Schedules, "cache_field_values")),
+		}),
+		IsFullSync:     types.BoolValue(source.IsFullSync),
+		IsOnDemand:     types.BoolValue(source.IsOnDemand),
+		AutoRunQueries: types.BoolValue(source.AutoRunQueries),
+		Refingerprint:  types.BoolPointerValue(source.Refingerprint),
+		CacheTTL:       cacheTTL,
+		Settings:       settings,
+	}, nil
+}
+
+// existingSchedule returns one cron string of a schedules object, or null.
+func existingSchedule(schedules types.Object, key string) types.String {
+	if schedules.IsNull() || schedules.IsUnknown() {
+		return types.StringNull()
+	}
+	if v, ok := schedules.Attributes()[key].(types.String); ok {
+		return v
 	}
+	return types.StringNull()
+}
+
+// reconcileSchedule keeps the existing cron string when it describes the same
+// schedule as Metabase's (which writes it in its own canonical form).
+func reconcileSchedule(api *string, existing types.String) types.String {
+	if api == nil || *api == "" {
+		return types.StringNull()
+	}
+	if !existing.IsNull() && !existing.IsUnknown() && metabase.SameCronSchedule(*api, existing.ValueString()) {
+		return existing
+	}
+	return types.StringValue(*api)
 }
 
 // ReconcileDetails returns the details to store in state: a non-secret key takes
@@ -77,3 +183,25 @@ func ReconcileDetails(apiDetails map[string]any, existingJSON string, redacted [
 	}
 	return string(b), nil
 }
+
+// DatabaseDataSourceTerraformModel is the metabase_database data source: the
+// connection details are not exposed.
+type DatabaseDataSourceTerraformModel struct {
+	Id     types.String `tfsdk:"id"`
+	Name   types.String `tfsdk:"name"`
+	Engine types.String `tfsdk:"engine"`
+}
+
+func CreateDatabaseDataSourceTerraformModelFromDTO(source *dtos.DatabaseDTO) DatabaseDataSourceTerraformModel {
+	return DatabaseDataSourceTerraformModel{
+		Id:     types.StringValue(strconv.Itoa(source.Id)),
+		Name:   types.StringValue(source.Name),
+		Engine: types.StringValue(source.Engine),
+	}
+}
+
+type DatabasesDataSourceTerraformModel struct {
+	Engine    types.String                       `tfsdk:"engine"`
+	NameRegex types.String                       `tfsdk:"name_regex"`
+	Databases []DatabaseDataSourceTerraformModel `tfsdk:"databases"`
+}
@@ -4,12 +4,96 @@
 package terraform
 
 import (
+	"encoding/json"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type DatabasePermissionTerraformModel struct {
-	Id            types.String `tfsdk:"id"`
-	GroupId       types.String `tfsdk:"group_id"`
-	DatabaseId    types.String `tfsdk:"database_id"`
-	CreateQueries types.String `tfsdk:"create_queries"`
+	Id                   types.String `tfsdk:"id"`
+	GroupId              types.String `tfsdk:"group_id"`
+	DatabaseId           types.String `tfsdk:"database_id"`
+	CreateQueries        types.String `tfsdk:"create_queries"`
+	CreateQueriesSchemas types.Map    `tfsdk:"create_queries_schemas"`
+	ViewData             types.String `tfsdk:"view_data"`
+	Download             types.String `tfsdk:"download"`
+	DownloadSchemas      types.Map    `tfsdk:"download_schemas"`
+	DataModel            types.String `tfsdk:"data_model"`
+	Details              types.String `tfsdk:"details"`
+}
+
+// SchemaCreateQueriesTerraformModel is the query-building access on one
+// schema: level for the whole schema, or tables (table id → level). It is the
+// element of the create_queries_schemas map.
+type SchemaCreateQueriesTerraformModel struct {
+	Level  types.String `tfsdk:"level"`
+	Tables types.Map    `tfsdk:"tables"`
+}
+
+var SchemaCreateQueriesType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"level":  types.StringType,
+	"tables": types.MapType{ElemType: types.StringType},
+}}
+
+// CreateDatabasePermissionTerraformModel builds the model from the edge read
+// from the graph. Granular query access is always read back, so it is
+// imported and drift to or from it shows up. The other optional levels are
+// refreshed only when existing manages them, so an unset attribute stays null
+// (also on import: every edge has a view-data level, even on OSS where it
+// can't be configured).
+func CreateDatabasePermissionTerraformModel(groupId string, databaseId string, source *dtos.DatabasePermissionDTO, existing DatabasePermissionTerraformModel) DatabasePermissionTerraformModel {
+	result := DatabasePermissionTerraformModel{
+		Id:                   types.StringValue(groupId + ":" + databaseId),
+		GroupId:              types.StringValue(groupId),
+		DatabaseId:           types.StringValue(databaseId),
+		CreateQueries:        types.StringValue(source.CreateQueries),
+		ViewData:             types.StringNull(),
+		Download:             types.StringNull(),
+		CreateQueriesSchemas: types.MapNull(SchemaCreateQueriesType),
+		DownloadSchemas:      types.MapNull(types.StringType),
+		DataModel:            types.StringNull(),
+		Details:              types.StringNull(),
+	}
+	if source.CreateQueriesSchemas != nil {
+		schemas := make(map[string]attr.Value, len(source.CreateQueriesSchemas))
+		for schema, p := range source.CreateQueriesSchemas {
+			level, tables := types.StringValue(p.Level), types.MapNull(types.StringType)
+			if p.Tables != nil {
+				level, tables = types.StringNull(), stringMap(p.Tables)
+			}
+			schemas[schema] = types.ObjectValueMust(SchemaCreateQueriesType.AttrTypes, map[string]attr.Value{"level": level, "tables": tables})
+		}
+		result.CreateQueriesSchemas = types.MapValueMust(SchemaCreateQueriesType, schemas)
+	}
+	if !existing.ViewData.IsNull() {
+		result.ViewData = types.StringPointerValue(source.ViewData)
+	}
+	// A database-wide level read back where per-schema levels are managed (or
+	// the reverse) is drift: report it in the attribute that is managed.
+	switch {
+	case !existing.DownloadSchemas.IsNull():
+		result.DownloadSchemas = stringMap(source.DownloadSchemas)
+	case !existing.Download.IsNull() && source.DownloadSchemas != nil:
+		b, _ := json.Marshal(source.DownloadSchemas)
+		result.Download = types.StringValue(string(b))
+	case !existing.Download.IsNull():
+		result.Download = types.StringPointerValue(source.Download)
+	}
+	if !existing.DataModel.IsNull() {
+		result.DataModel = types.StringPointerValue(source.DataModel)
+	}
+	if !existing.Details.IsNull() {
+		result.Details = types.StringPointerValue(source.Details)
+	}
+	return result
+}
+
+func stringMap(values map[string]string) types.Map {
+	elements := make(map[string]attr.Value, len(values))
+	for k, v := range values {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
 }
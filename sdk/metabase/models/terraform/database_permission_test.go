@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestCreateDatabasePermissionTerraformModel(t *testing.T) {
+	unmanaged := DatabasePermissionTerraformModel{
+		GroupId:              types.StringValue("3"),
+		CreateQueriesSchemas: types.MapNull(SchemaCreateQueriesType),
+		ViewData:             types.StringNull(),
+		Download:             types.StringNull(),
+		DownloadSchemas:      types.MapNull(types.StringType),
+		DataModel:            types.StringNull(),
+		Details:              types.StringNull(),
+	}
+	source := &dtos.DatabasePermissionDTO{
+		CreateQueries: "query-builder",
+		ViewData:      strPtr("unrestricted"),
+		Download:      strPtr("full"),
+		DataModel:     strPtr("all"),
+		Details:       strPtr("no"),
+	}
+
+	t.Run("unset levels stay null", func(t *testing.T) {
+		got := CreateDatabasePermissionTerraformModel("3", "7", source, unmanaged)
+		if got.Id.ValueString() != "3:7" || got.CreateQueries.ValueString() != "query-builder" {
+			t.Fatalf("unexpected id/create_queries: %s %s", got.Id, got.CreateQueries)
+		}
+		for name, v := range map[string]attr.Value{"view_data": got.ViewData, "download": got.Download, "download_schemas": got.DownloadSchemas, "data_model": got.DataModel, "details": got.Details} {
+			if !v.IsNull() {
+				t.Errorf("expected %s to stay null, got %s", name, v)
+			}
+		}
+	})
+
+	t.Run("managed levels are refreshed", func(t *testing.T) {
+		existing := unmanaged
+		existing.ViewData = types.StringValue("blocked")
+		existing.Download = types.StringValue("limited")
+		existing.Details = types.StringValue("yes")
+
+		got := CreateDatabasePermissionTerraformModel("3", "7", source, existing)
+		if got.ViewData.ValueString() != "unrestricted" || got.Download.ValueString() != "full" || got.Details.ValueString() != "no" {
+			t.Errorf("expected the graph's levels, got view_data=%s download=%s details=%s", got.ViewData, got.Download, got.Details)
+		}
+		if !got.DataModel.IsNull() {
+			t.Errorf("expected data_model to stay null, got %s", got.DataModel)
+		}
+	})
+
+	t.Run("managed level missing from the graph reads as null", func(t *testing.T) {
+		existing := unmanaged
+		existing.DataModel = types.StringValue("all")
+
+		got := CreateDatabasePermissionTerraformModel("3", "7", &dtos.DatabasePermissionDTO{CreateQueries: "no"}, existing)
+		if !got.DataModel.IsNull() {
+			t.Errorf("expected data_model drift to null, got %s", got.DataModel)
+		}
+	})
+
+	t.Run("per-schema download", func(t *testing.T) {
+		perSchema := &dtos.DatabasePermissionDTO{CreateQueries: "no", DownloadSchemas: map[string]string{"public": "full", "sales": "none"}}
+
+		existing := unmanaged
+		existing.DownloadSchemas = types.MapValueMust(types.StringType, map[string]attr.Value{"public": types.StringValue("full")})
+		got := CreateDatabasePermissionTerraformModel("3", "7", perSchema, existing)
+		want := types.MapValueMust(types.StringType, map[string]attr.Value{"public": types.StringValue("full"), "sales": types.StringValue("none")})
+		if !got.DownloadSchemas.Equal(want) {
+			t.Errorf("expected %s, got %s", want, got.DownloadSchemas)
+		}
+
+		// Managed database-wide, granular in the graph: drift in download.
+		existing = unmanaged
+		existing.Download = types.StringValue("full")
+		got = CreateDatabasePermissionTerraformModel("3", "7", perSchema, existing)
+		if got.Download.ValueString() != `{"public":"full","sales":"none"}` {
+			t.Errorf("expected the per-schema levels as drift, got %s", got.Download)
+		}
+	})
+}
+
+func TestCreateDatabasePermissionTerraformModel_Granular(t *testing.T) {
+	source := &dtos.DatabasePermissionDTO{
+		CreateQueries: "granular",
+		CreateQueriesSchemas: map[string]dtos.SchemaPermissionDTO{
+			"finance":   {Level: "query-builder"},
+			"reporting": {Tables: map[string]string{"42": "query-builder", "43": "no"}},
+		},
+	}
+
+	// Always read back, also when unset (import, drift from a flat level).
+	got := CreateDatabasePermissionTerraformModel("3", "7", source, DatabasePermissionTerraformModel{})
+	if got.CreateQueries.ValueString() != "granular" {
+		t.Errorf("expected create_queries \"granular\", got %s", got.CreateQueries)
+	}
+	want := types.MapValueMust(SchemaCreateQueriesType, map[string]attr.Value{
+		"finance": types.ObjectValueMust(SchemaCreateQueriesType.AttrTypes, map[string]attr.Value{
+			"level":  types.StringValue("query-builder"),
+			"tables": types.MapNull(types.StringType),
+		}),
+		"reporting": types.ObjectValueMust(SchemaCreateQueriesType.AttrTypes, map[string]attr.Value{
+			"level":  types.StringNull(),
+			"tables": types.MapValueMust(types.StringType, map[string]attr.Value{"42": types.StringValue("query-builder"), "43": types.StringValue("no")}),
+		}),
+	})
+	if !got.CreateQueriesSchemas.Equal(want) {
+		t.Errorf("expected %s, got %s", want, got.CreateQueriesSchemas)
+	}
+
+	flat := CreateDatabasePermissionTerraformModel("3", "7", &dtos.DatabasePermissionDTO{CreateQueries: "no"}, DatabasePermissionTerraformModel{})
+	if !flat.CreateQueriesSchemas.IsNull() {
+		t.Errorf("expected no schemas for a database-wide level, got %s", flat.CreateQueriesSchemas)
+	}
+}
@@ -6,6 +6,10 @@ package terraform
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func mustDecode(t *testing.T, s string) map[string]any {
@@ -79,3 +83,80 @@ func TestReconcileDetails(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateDatabaseTerraformModelFromDTO(t *testing.T) {
+	refingerprint, cacheTTL := true, 24
+	source := &dtos.DatabaseDTO{
+		Id:                       4,
+		Name:                     "Warehouse",
+		Engine:                   "postgres",
+		MetadataSyncSchedule:     "0 0 2 * * ? *",
+		CacheFieldValuesSchedule: strPtr("0 0 3 ? * 2 *"),
+		IsFullSync:               true,
+		Refingerprint:            &refingerprint,
+		CacheTTL:                 &cacheTTL,
+		Settings:                 map[string]any{"database-enable-actions": true, "added-by-metabase": 1.0},
+	}
+	existing := DatabaseTerraformModel{
+		Details:            types.StringValue(`{"host":"db"}`),
+		RedactedAttributes: types.SetNull(types.StringType),
+		DeletionProtection: types.BoolValue(false),
+		Schedules: types.ObjectValueMust(DatabaseSchedulesType.AttrTypes, map[string]attr.Value{
+			"metadata_sync":      types.StringValue("0 0 2 * * ?"),
+			"cache_field_values": types.StringValue("0 0 4 ? * MON *"),
+		}),
+		Settings: NewJSONValue(`{"database-enable-actions":true}`),
+	}
+
+	got, err := CreateDatabaseTerraformModelFromDTO(source, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Id.ValueString() != "4" || got.Details.ValueString() != `{"host":"db"}` || got.DeletionProtection.ValueBool() {
+		t.Errorf("unexpected id/details/deletion_protection: %s %s %s", got.Id, got.Details, got.DeletionProtection)
+	}
+	schedules := got.Schedules.Attributes()
+	// Equivalent cron keeps the config's spelling; a different one is drift.
+	if schedules["metadata_sync"].(types.String).ValueString() != "0 0 2 * * ?" {
+		t.Errorf("expected the existing metadata_sync spelling, got %s", schedules["metadata_sync"])
+	}
+	if schedules["cache_field_values"].(types.String).ValueString() != "0 0 3 ? * 2 *" {
+		t.Errorf("expected cache_field_values drift, got %s", schedules["cache_field_values"])
+	}
+	if !got.IsFullSync.ValueBool() || got.IsOnDemand.ValueBool() || got.AutoRunQueries.ValueBool() || !got.Refingerprint.ValueBool() || got.CacheTTL.ValueInt64() != 24 {
+		t.Errorf("unexpected options: %s %s %s %s %s", got.IsFullSync, got.IsOnDemand, got.AutoRunQueries, got.Refingerprint, got.CacheTTL)
+	}
+	if got.Settings.ValueString() != `{"database-enable-actions":true}` {
+		t.Errorf("expected keys added by Metabase to be ignored, got %s", got.Settings)
+	}
+
+	t.Run("unmanaged settings stay null", func(t *testing.T) {
+		unmanaged := existing
+		unmanaged.Settings = NewJSONNull()
+		got, err := CreateDatabaseTerraformModelFromDTO(source, unmanaged)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Settings.IsNull() {
+			t.Errorf("expected settings to stay null, got %s", got.Settings)
+		}
+	})
+
+	t.Run("removed settings and unscheduled scan", func(t *testing.T) {
+		cleared := *source
+		cleared.Settings = nil
+		cleared.CacheFieldValuesSchedule = nil
+		cleared.Refingerprint = nil
+		cleared.CacheTTL = nil
+		got, err := CreateDatabaseTerraformModelFromDTO(&cleared, existing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Settings.ValueString() != `{}` {
+			t.Errorf("expected settings drift to {}, got %s", got.Settings)
+		}
+		if !got.Schedules.Attributes()["cache_field_values"].IsNull() || !got.Refingerprint.IsNull() || !got.CacheTTL.IsNull() {
+			t.Errorf("expected nulls, got %s %s %s", got.Schedules, got.Refingerprint, got.CacheTTL)
+		}
+	})
+}
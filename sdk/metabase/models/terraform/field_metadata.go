@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type FieldMetadataTerraformModel struct {
+	Id              types.String `tfsdk:"id"`
+	TableId         types.String `tfsdk:"table_id"`
+	Name            types.String `tfsdk:"name"`
+	DisplayName     types.String `tfsdk:"display_name"`
+	Description     types.String `tfsdk:"description"`
+	SemanticType    types.String `tfsdk:"semantic_type"`
+	VisibilityType  types.String `tfsdk:"visibility_type"`
+	FkTargetFieldId types.String `tfsdk:"fk_target_field_id"`
+}
+
+// CreateFieldMetadataTerraformModelFromDTO builds the model from the field.
+// Cleared values (null in Metabase) read as "", which is how they are set.
+func CreateFieldMetadataTerraformModelFromDTO(source *dtos.FieldDTO) FieldMetadataTerraformModel {
+	fkTarget := ""
+	if source.FkTargetFieldId != nil {
+		fkTarget = strconv.Itoa(*source.FkTargetFieldId)
+	}
+	return FieldMetadataTerraformModel{
+		Id:              types.StringValue(strconv.Itoa(source.Id)),
+		TableId:         types.StringValue(strconv.Itoa(source.TableId)),
+		Name:            types.StringValue(source.Name),
+		DisplayName:     types.StringValue(source.DisplayName),
+		Description:     types.StringValue(valueOrEmpty(source.Description)),
+		SemanticType:    types.StringValue(valueOrEmpty(source.SemanticType)),
+		VisibilityType:  types.StringValue(source.VisibilityType),
+		FkTargetFieldId: types.StringValue(fkTarget),
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+func TestCreateFieldMetadataTerraformModelFromDTO(t *testing.T) {
+	t.Run("foreign key", func(t *testing.T) {
+		got := CreateFieldMetadataTerraformModelFromDTO(&dtos.FieldDTO{
+			Id: 12, TableId: 3, Name: "USER_ID", DisplayName: "Buyer",
+			SemanticType: strPtr("type/FK"), VisibilityType: "normal", FkTargetFieldId: intPtr(40),
+		})
+		if got.Id.ValueString() != "12" || got.TableId.ValueString() != "3" || got.FkTargetFieldId.ValueString() != "40" {
+			t.Errorf("unexpected ids: id=%s table_id=%s fk_target_field_id=%s", got.Id, got.TableId, got.FkTargetFieldId)
+		}
+	})
+
+	// Cleared values read as "" (how they are set), not null.
+	t.Run("cleared values", func(t *testing.T) {
+		got := CreateFieldMetadataTerraformModelFromDTO(&dtos.FieldDTO{Id: 12, TableId: 3, Name: "TOTAL", VisibilityType: "normal"})
+		for name, v := range map[string]string{"description": got.Description.ValueString(), "semantic_type": got.SemanticType.ValueString(), "fk_target_field_id": got.FkTargetFieldId.ValueString()} {
+			if v != "" {
+				t.Errorf("expected %s to be \"\", got %q", name, v)
+			}
+		}
+		if got.Description.IsNull() || got.SemanticType.IsNull() || got.FkTargetFieldId.IsNull() {
+			t.Error("expected cleared values to be known, not null")
+		}
+	})
+}
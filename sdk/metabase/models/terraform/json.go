@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ReconcileJSON returns the JSON to store in state for a value Metabase
+// normalizes (e.g. a card's dataset_query): existingJSON verbatim when the API
+// value still contains it — same values, plus keys Metabase adds — otherwise
+// the API value, so real drift shows up. An empty existingJSON (import) takes
+// the API value.
+func ReconcileJSON(apiJSON json.RawMessage, existingJSON string) (string, error) {
+	if len(bytes.TrimSpace(apiJSON)) == 0 || bytes.Equal(bytes.TrimSpace(apiJSON), []byte("null")) {
+		return existingJSON, nil
+	}
+	var api any
+	if err := json.Unmarshal(apiJSON, &api); err != nil {
+		return "", fmt.Errorf("invalid JSON from Metabase: %w", err)
+	}
+	if existingJSON != "" {
+		var existing any
+		if err := json.Unmarshal([]byte(existingJSON), &existing); err != nil {
+			return "", fmt.Errorf("invalid JSON in state: %w", err)
+		}
+		if jsonContains(api, existing) {
+			return existingJSON, nil
+		}
+	}
+	b, err := json.Marshal(api)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// jsonContains reports whether want is api with, at most, object keys removed
+// at any depth. Arrays must match element by element.
+func jsonContains(api any, want any) bool {
+	switch w := want.(type) {
+	case map[string]any:
+		a, ok := api.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, wv := range w {
+			av, ok := a[k]
+			if !ok {
+				// Metabase drops null keys; an explicit null in config matches absence.
+				if wv == nil {
+					continue
+				}
+				return false
+			}
+			if !jsonContains(av, wv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		a, ok := api.([]any)
+		if !ok || len(a) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !jsonContains(a[i], w[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(api, want)
+	}
+}
+
+// JSONEqual reports whether two JSON documents are semantically equal
+// (ignoring formatting and key order).
+func JSONEqual(a, b string) bool {
+	var av, bv any
+	if json.Unmarshal([]byte(a), &av) != nil || json.Unmarshal([]byte(b), &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReconcileJSON(t *testing.T) {
+	const state = `{"database":1,"type":"native","native":{"query":"SELECT 1"}}`
+
+	tests := []struct {
+		name     string
+		api      string
+		existing string
+		want     string
+	}{
+		{
+			name:     "metabase-added keys keep state verbatim",
+			api:      `{"database":1,"type":"native","native":{"query":"SELECT 1","template-tags":{}},"lib/type":"mbql/query"}`,
+			existing: state,
+			want:     state,
+		},
+		{
+			name:     "a changed value is drift",
+			api:      `{"database":1,"type":"native","native":{"query":"SELECT 2"}}`,
+			existing: state,
+			want:     `{"database":1,"native":{"query":"SELECT 2"},"type":"native"}`,
+		},
+		{
+			name:     "a removed key is drift",
+			api:      `{"database":1,"type":"native"}`,
+			existing: state,
+			want:     `{"database":1,"type":"native"}`,
+		},
+		{
+			name:     "a null in state matches an omitted key",
+			api:      `{"graph.dimensions":["x"]}`,
+			existing: `{"graph.dimensions":["x"],"graph.metrics":null}`,
+			want:     `{"graph.dimensions":["x"],"graph.metrics":null}`,
+		},
+		{
+			name:     "arrays must match element by element",
+			api:      `{"graph.dimensions":["x","y"]}`,
+			existing: `{"graph.dimensions":["x"]}`,
+			want:     `{"graph.dimensions":["x","y"]}`,
+		},
+		{
+			name:     "empty state (import) takes the API value",
+			api:      `{"b":1,"a":2}`,
+			existing: "",
+			want:     `{"a":2,"b":1}`,
+		},
+		{
+			name:     "a null API value keeps state",
+			api:      `null`,
+			existing: state,
+			want:     state,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReconcileJSON(json.RawMessage(tt.api), tt.existing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONEqual(t *testing.T) {
+	if !JSONEqual(`{"a": 1, "b": [1, 2]}`, `{"b":[1,2],"a":1}`) {
+		t.Errorf("expected formatting and key order to be ignored")
+	}
+	if JSONEqual(`{"a": 1}`, `{"a": 2}`) {
+		t.Errorf("expected different values to differ")
+	}
+	if JSONEqual(`not json`, `not json`) {
+		t.Errorf("expected invalid JSON never to be equal")
+	}
+}
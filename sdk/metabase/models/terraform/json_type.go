@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ basetypes.StringTypable = JSONType{}
+var _ basetypes.StringValuableWithSemanticEquals = JSONValue{}
+
+// JSONType is a string attribute holding a JSON document. Values that only
+// differ in formatting or key order are semantically equal, so re-indenting
+// a jsonencode'd value never plans an update.
+type JSONType struct {
+	basetypes.StringType
+}
+
+func (t JSONType) Equal(o attr.Type) bool {
+	other, ok := o.(JSONType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t JSONType) String() string {
+	return "terraform.JSONType"
+}
+
+func (t JSONType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return JSONValue{StringValue: in}, nil
+}
+
+func (t JSONType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	return JSONValue{StringValue: stringValue}, nil
+}
+
+func (t JSONType) ValueType(_ context.Context) attr.Value {
+	return JSONValue{}
+}
+
+// JSONValue is the value of a JSONType attribute.
+type JSONValue struct {
+	basetypes.StringValue
+}
+
+func NewJSONValue(value string) JSONValue {
+	return JSONValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func NewJSONNull() JSONValue {
+	return JSONValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v JSONValue) Type(_ context.Context) attr.Type {
+	return JSONType{}
+}
+
+func (v JSONValue) Equal(o attr.Value) bool {
+	other, ok := o.(JSONValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v JSONValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(JSONValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("Expected terraform.JSONValue, got %T.", newValuable))
+		return false, diags
+	}
+	return JSONEqual(v.ValueString(), newValue.ValueString()), diags
+}
@@ -21,3 +21,8 @@ func CreatePermissionGroupTerraformModelFromDTO(source *dtos.PermissionGroupDTO)
 	}
 
 }
+
+type PermissionGroupsDataSourceTerraformModel struct {
+	NameRegex types.String                    `tfsdk:"name_regex"`
+	Groups    []PermissionGroupTerraformModel `tfsdk:"groups"`
+}
@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type PermissionGroupMembersTerraformModel struct {
+	Id            types.String `tfsdk:"id"`
+	GroupId       types.String `tfsdk:"group_id"`
+	Members       types.Set    `tfsdk:"members"`
+	GroupManagers types.Set    `tfsdk:"group_managers"`
+}
+
+// CreatePermissionGroupMembersTerraformModelFromDTO builds the model from the
+// group's memberships. group_managers stays null unless it is managed.
+func CreatePermissionGroupMembersTerraformModelFromDTO(groupId string, source []dtos.UserPermissionGroupMembershipDTO, existing PermissionGroupMembersTerraformModel) PermissionGroupMembersTerraformModel {
+	members := []attr.Value{}
+	managers := []attr.Value{}
+	for _, m := range source {
+		userId := types.StringValue(strconv.Itoa(m.UserId))
+		members = append(members, userId)
+		if m.IsGroupManager {
+			managers = append(managers, userId)
+		}
+	}
+
+	result := PermissionGroupMembersTerraformModel{
+		Id:            types.StringValue(groupId),
+		GroupId:       types.StringValue(groupId),
+		Members:       types.SetValueMust(types.StringType, members),
+		GroupManagers: types.SetNull(types.StringType),
+	}
+	if !existing.GroupManagers.IsNull() {
+		result.GroupManagers = types.SetValueMust(types.StringType, managers)
+	}
+	return result
+}
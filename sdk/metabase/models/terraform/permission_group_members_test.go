@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCreatePermissionGroupMembersTerraformModelFromDTO(t *testing.T) {
+	source := []dtos.UserPermissionGroupMembershipDTO{
+		{MembershipId: 10, UserId: 1, GroupId: 3},
+		{MembershipId: 11, UserId: 2, GroupId: 3, IsGroupManager: true},
+	}
+	members := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("1"), types.StringValue("2")})
+
+	// Managers are not read back unless they are managed.
+	t.Run("unmanaged group managers", func(t *testing.T) {
+		got := CreatePermissionGroupMembersTerraformModelFromDTO("3", source, PermissionGroupMembersTerraformModel{GroupManagers: types.SetNull(types.StringType)})
+		if got.Id.ValueString() != "3" || got.GroupId.ValueString() != "3" {
+			t.Errorf("unexpected ids: id=%s group_id=%s", got.Id, got.GroupId)
+		}
+		if !got.Members.Equal(members) {
+			t.Errorf("expected members %s, got %s", members, got.Members)
+		}
+		if !got.GroupManagers.IsNull() {
+			t.Errorf("expected group_managers to be null, got %s", got.GroupManagers)
+		}
+	})
+
+	t.Run("managed group managers", func(t *testing.T) {
+		existing := PermissionGroupMembersTerraformModel{GroupManagers: types.SetValueMust(types.StringType, nil)}
+		got := CreatePermissionGroupMembersTerraformModelFromDTO("3", source, existing)
+		want := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("2")})
+		if !got.GroupManagers.Equal(want) {
+			t.Errorf("expected group_managers %s, got %s", want, got.GroupManagers)
+		}
+	})
+
+	t.Run("no members", func(t *testing.T) {
+		got := CreatePermissionGroupMembersTerraformModelFromDTO("3", nil, PermissionGroupMembersTerraformModel{GroupManagers: types.SetNull(types.StringType)})
+		if got.Members.IsNull() || len(got.Members.Elements()) != 0 {
+			t.Errorf("expected an empty members set, got %s", got.Members)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type PermissionsGraphTerraformModel struct {
+	Id          types.String `tfsdk:"id"`
+	GroupId     types.String `tfsdk:"group_id"`
+	Databases   types.Map    `tfsdk:"databases"`
+	Collections types.Map    `tfsdk:"collections"`
+}
+
+// CreatePermissionsGraphTerraformModel builds the model from the group's
+// actual grants. An attribute left unset in existing stays null while the
+// group has no grants of that kind.
+func CreatePermissionsGraphTerraformModel(groupId string, databases map[string]string, collections map[string]string, existing PermissionsGraphTerraformModel) PermissionsGraphTerraformModel {
+	return PermissionsGraphTerraformModel{
+		Id:          types.StringValue(groupId),
+		GroupId:     types.StringValue(groupId),
+		Databases:   grantsMap(databases, existing.Databases),
+		Collections: grantsMap(collections, existing.Collections),
+	}
+}
+
+func grantsMap(grants map[string]string, existing types.Map) types.Map {
+	if len(grants) == 0 && existing.IsNull() {
+		return types.MapNull(types.StringType)
+	}
+	elements := make(map[string]attr.Value, len(grants))
+	for k, v := range grants {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
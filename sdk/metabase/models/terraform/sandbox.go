@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type SandboxTerraformModel struct {
+	Id                  types.String `tfsdk:"id"`
+	GroupId             types.String `tfsdk:"group_id"`
+	TableId             types.String `tfsdk:"table_id"`
+	CardId              types.String `tfsdk:"card_id"`
+	AttributeRemappings types.Map    `tfsdk:"attribute_remappings"`
+	PreviousViewData    types.String `tfsdk:"previous_view_data"`
+}
+
+// SandboxTargetTerraformModel is what a user attribute filters: field_id or
+// template_tag. It is the element of the attribute_remappings map.
+type SandboxTargetTerraformModel struct {
+	FieldId     types.String `tfsdk:"field_id"`
+	TemplateTag types.String `tfsdk:"template_tag"`
+}
+
+var SandboxTargetType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"field_id":     types.StringType,
+	"template_tag": types.StringType,
+}}
+
+// CreateSandboxTerraformModelFromDTO builds the model from the sandbox. No
+// remappings read as null unless existing sets them (to an empty map).
+// previous_view_data is kept from existing, "blocked" when unknown (e.g. on
+// import).
+func CreateSandboxTerraformModelFromDTO(source *dtos.SandboxDTO, existing SandboxTerraformModel) SandboxTerraformModel {
+	result := SandboxTerraformModel{
+		Id:                  types.StringValue(strconv.Itoa(source.Id)),
+		GroupId:             types.StringValue(strconv.Itoa(source.GroupId)),
+		TableId:             types.StringValue(strconv.Itoa(source.TableId)),
+		CardId:              types.StringNull(),
+		AttributeRemappings: types.MapNull(SandboxTargetType),
+		PreviousViewData:    existing.PreviousViewData,
+	}
+	if existing.PreviousViewData.IsNull() || existing.PreviousViewData.IsUnknown() {
+		result.PreviousViewData = types.StringValue("blocked")
+	}
+	if source.CardId != nil {
+		result.CardId = types.StringValue(strconv.Itoa(*source.CardId))
+	}
+	if len(source.AttributeRemappings) == 0 && existing.AttributeRemappings.IsNull() {
+		return result
+	}
+	remappings := make(map[string]attr.Value, len(source.AttributeRemappings))
+	for attribute, target := range source.AttributeRemappings {
+		fieldId := types.StringNull()
+		if target.FieldId != nil {
+			fieldId = types.StringValue(strconv.Itoa(*target.FieldId))
+		}
+		remappings[attribute] = types.ObjectValueMust(SandboxTargetType.AttrTypes, map[string]attr.Value{
+			"field_id":     fieldId,
+			"template_tag": types.StringPointerValue(target.TemplateTag),
+		})
+	}
+	result.AttributeRemappings = types.MapValueMust(SandboxTargetType, remappings)
+	return result
+}
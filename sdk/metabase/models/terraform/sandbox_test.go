@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCreateSandboxTerraformModelFromDTO(t *testing.T) {
+	t.Run("table sandbox", func(t *testing.T) {
+		got := CreateSandboxTerraformModelFromDTO(&dtos.SandboxDTO{
+			Id: 7, GroupId: 3, TableId: 12,
+			AttributeRemappings: map[string]dtos.SandboxTargetDTO{
+				"account_id": {FieldId: intPtr(40)},
+			},
+		}, SandboxTerraformModel{})
+		if got.Id.ValueString() != "7" || got.GroupId.ValueString() != "3" || got.TableId.ValueString() != "12" {
+			t.Errorf("unexpected ids: id=%s group_id=%s table_id=%s", got.Id, got.GroupId, got.TableId)
+		}
+		if !got.CardId.IsNull() {
+			t.Errorf("expected card_id to be null, got %s", got.CardId)
+		}
+		if got.PreviousViewData.ValueString() != "blocked" {
+			t.Errorf("expected previous_view_data to default to blocked, got %s", got.PreviousViewData)
+		}
+		target, ok := got.AttributeRemappings.Elements()["account_id"].(types.Object)
+		if !ok {
+			t.Fatalf("expected an account_id remapping, got %s", got.AttributeRemappings)
+		}
+		if v := target.Attributes()["field_id"].(types.String); v.ValueString() != "40" {
+			t.Errorf("expected field_id 40, got %s", v)
+		}
+		if v := target.Attributes()["template_tag"].(types.String); !v.IsNull() {
+			t.Errorf("expected template_tag to be null, got %s", v)
+		}
+	})
+
+	// No remappings stay null unless they are managed as an empty map.
+	t.Run("no remappings", func(t *testing.T) {
+		source := &dtos.SandboxDTO{Id: 7, GroupId: 3, TableId: 12, CardId: intPtr(5)}
+		got := CreateSandboxTerraformModelFromDTO(source, SandboxTerraformModel{AttributeRemappings: types.MapNull(SandboxTargetType), PreviousViewData: types.StringValue("unrestricted")})
+		if got.CardId.ValueString() != "5" {
+			t.Errorf("expected card_id 5, got %s", got.CardId)
+		}
+		if got.PreviousViewData.ValueString() != "unrestricted" {
+			t.Errorf("expected previous_view_data to be kept, got %s", got.PreviousViewData)
+		}
+		if !got.AttributeRemappings.IsNull() {
+			t.Errorf("expected attribute_remappings to be null, got %s", got.AttributeRemappings)
+		}
+
+		empty := types.MapValueMust(SandboxTargetType, nil)
+		got = CreateSandboxTerraformModelFromDTO(source, SandboxTerraformModel{AttributeRemappings: empty})
+		if got.AttributeRemappings.IsNull() || len(got.AttributeRemappings.Elements()) != 0 {
+			t.Errorf("expected an empty attribute_remappings, got %s", got.AttributeRemappings)
+		}
+	})
+}
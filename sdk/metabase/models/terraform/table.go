@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type TableTerraformModel struct {
+	Id          types.String `tfsdk:"id"`
+	DatabaseId  types.String `tfsdk:"database_id"`
+	Schema      types.String `tfsdk:"schema"`
+	Name        types.String `tfsdk:"name"`
+	DisplayName types.String `tfsdk:"display_name"`
+	Description types.String `tfsdk:"description"`
+}
+
+func CreateTableTerraformModelFromDTO(source *dtos.TableDTO) TableTerraformModel {
+	return TableTerraformModel{
+		Id:          types.StringValue(strconv.Itoa(source.Id)),
+		DatabaseId:  types.StringValue(strconv.Itoa(source.DbId)),
+		Schema:      types.StringPointerValue(source.Schema),
+		Name:        types.StringValue(source.Name),
+		DisplayName: types.StringValue(source.DisplayName),
+		Description: types.StringPointerValue(source.Description),
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type TableMetadataTerraformModel struct {
+	Id             types.String `tfsdk:"id"`
+	DatabaseId     types.String `tfsdk:"database_id"`
+	Schema         types.String `tfsdk:"schema"`
+	Name           types.String `tfsdk:"name"`
+	DisplayName    types.String `tfsdk:"display_name"`
+	Description    types.String `tfsdk:"description"`
+	VisibilityType types.String `tfsdk:"visibility_type"`
+	EntityType     types.String `tfsdk:"entity_type"`
+}
+
+// CreateTableMetadataTerraformModelFromDTO builds the model from the table.
+// Cleared values (null in Metabase) read as "", which is how they are set.
+func CreateTableMetadataTerraformModelFromDTO(source *dtos.TableDTO) TableMetadataTerraformModel {
+	return TableMetadataTerraformModel{
+		Id:             types.StringValue(strconv.Itoa(source.Id)),
+		DatabaseId:     types.StringValue(strconv.Itoa(source.DbId)),
+		Schema:         types.StringPointerValue(source.Schema),
+		Name:           types.StringValue(source.Name),
+		DisplayName:    types.StringValue(source.DisplayName),
+		Description:    types.StringValue(valueOrEmpty(source.Description)),
+		VisibilityType: types.StringValue(valueOrEmpty(source.VisibilityType)),
+		EntityType:     types.StringValue(valueOrEmpty(source.EntityType)),
+	}
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
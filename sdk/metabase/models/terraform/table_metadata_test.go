@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+func TestCreateTableMetadataTerraformModelFromDTO(t *testing.T) {
+	got := CreateTableMetadataTerraformModelFromDTO(&dtos.TableDTO{
+		Id: 7, DbId: 1, Schema: strPtr("PUBLIC"), Name: "PRODUCTS", DisplayName: "Catalog",
+		VisibilityType: strPtr("technical"), EntityType: strPtr("entity/ProductTable"),
+	})
+	if got.DatabaseId.ValueString() != "1" || got.Schema.ValueString() != "PUBLIC" || got.VisibilityType.ValueString() != "technical" {
+		t.Errorf("unexpected model: %+v", got)
+	}
+	if got.Description.IsNull() || got.Description.ValueString() != "" {
+		t.Errorf("expected no description to read as \"\", got %s", got.Description)
+	}
+
+	queryable := CreateTableMetadataTerraformModelFromDTO(&dtos.TableDTO{Id: 7, DbId: 1, Name: "PRODUCTS"})
+	if queryable.VisibilityType.ValueString() != "" || !queryable.Schema.IsNull() {
+		t.Errorf("expected visibility_type \"\" and a null schema, got %s and %s", queryable.VisibilityType, queryable.Schema)
+	}
+}
@@ -4,26 +4,54 @@
 package terraform
 
 import (
+	"encoding/json"
+	"strconv"
+
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"strconv"
 )
 
 type UserTerraformModel struct {
-	Id        types.String `tfsdk:"id"`
-	Email     types.String `tfsdk:"email"`
-	FirstName types.String `tfsdk:"first_name"`
-	LastName  types.String `tfsdk:"last_name"`
-	IsActive  types.Bool   `tfsdk:"is_active"`
+	Id              types.String `tfsdk:"id"`
+	Email           types.String `tfsdk:"email"`
+	FirstName       types.String `tfsdk:"first_name"`
+	LastName        types.String `tfsdk:"last_name"`
+	IsActive        types.Bool   `tfsdk:"is_active"`
+	IsSuperuser     types.Bool   `tfsdk:"is_superuser"`
+	LoginAttributes types.Map    `tfsdk:"login_attributes"`
+	Locale          types.String `tfsdk:"locale"`
+	SsoSource       types.String `tfsdk:"sso_source"`
 }
 
+// CreateUserTerraformModelFromDTO builds the model from the user. The default
+// locale reads as "" (how it is set); login attribute values that are not
+// strings read as their JSON encoding.
 func CreateUserTerraformModelFromDTO(source *dtos.UserDTO) UserTerraformModel {
+	attributes := make(map[string]attr.Value, len(source.LoginAttributes))
+	for k, v := range source.LoginAttributes {
+		s, ok := v.(string)
+		if !ok {
+			b, _ := json.Marshal(v)
+			s = string(b)
+		}
+		attributes[k] = types.StringValue(s)
+	}
 	return UserTerraformModel{
-		Id:        types.StringValue(strconv.Itoa(source.Id)),
-		Email:     types.StringValue(source.Email),
-		FirstName: types.StringValue(source.FirstName),
-		LastName:  types.StringValue(source.LastName),
-		IsActive:  types.BoolValue(source.IsActive),
+		Id:              types.StringValue(strconv.Itoa(source.Id)),
+		Email:           types.StringValue(source.Email),
+		FirstName:       types.StringValue(source.FirstName),
+		LastName:        types.StringValue(source.LastName),
+		IsActive:        types.BoolValue(source.IsActive),
+		IsSuperuser:     types.BoolValue(source.IsSuperuser),
+		LoginAttributes: types.MapValueMust(types.StringType, attributes),
+		Locale:          types.StringValue(valueOrEmpty(source.Locale)),
+		SsoSource:       types.StringPointerValue(source.SsoSource),
 	}
+}
 
+type UsersDataSourceTerraformModel struct {
+	Status     types.String         `tfsdk:"status"`
+	EmailRegex types.String         `tfsdk:"email_regex"`
+	Users      []UserTerraformModel `tfsdk:"users"`
 }
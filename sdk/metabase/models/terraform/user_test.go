@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCreateUserTerraformModelFromDTO(t *testing.T) {
+	got := CreateUserTerraformModelFromDTO(&dtos.UserDTO{
+		Id: 4, Email: "jane@example.com", IsActive: true, IsSuperuser: true,
+		LoginAttributes: map[string]any{"account_id": "42", "level": float64(3)},
+	})
+	if !got.IsSuperuser.ValueBool() {
+		t.Error("expected is_superuser to be true")
+	}
+	want := map[string]string{"account_id": "42", "level": "3"}
+	for k, v := range want {
+		if got, ok := got.LoginAttributes.Elements()[k].(types.String); !ok || got.ValueString() != v {
+			t.Errorf("expected login attribute %s = %q, got %v", k, v, got)
+		}
+	}
+	// The default locale reads as "" (how it is set); no SSO is null.
+	if got.Locale.IsNull() || got.Locale.ValueString() != "" {
+		t.Errorf("expected locale \"\", got %s", got.Locale)
+	}
+	if !got.SsoSource.IsNull() {
+		t.Errorf("expected sso_source to be null, got %s", got.SsoSource)
+	}
+	if got.LoginAttributes.IsNull() {
+		t.Error("expected login_attributes to be known")
+	}
+
+	empty := CreateUserTerraformModelFromDTO(&dtos.UserDTO{Id: 5})
+	if empty.LoginAttributes.IsNull() || len(empty.LoginAttributes.Elements()) != 0 {
+		t.Errorf("expected no login attributes to read as an empty map, got %s", empty.LoginAttributes)
+	}
+}
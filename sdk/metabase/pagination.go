@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// DefaultPageSize is the page size used by ListAll.
+const DefaultPageSize = 100
+
+// pageEnvelope is the {data, total, limit, offset} shape of paginated
+// listings. Limit and Offset echo the request; they are null when the
+// endpoint ignored pagination and returned everything.
+type pageEnvelope[T any] struct {
+	Data   []T  `json:"data"`
+	Total  *int `json:"total"`
+	Limit  *int `json:"limit"`
+	Offset *int `json:"offset"`
+}
+
+// Paginate iterates over every item of the listing at path (which may already
+// carry query parameters), requesting pages of pageSize with limit/offset. It
+// accepts both listing shapes Metabase uses: a {data, total} envelope, walked
+// until total items are seen (or a short page when total is absent), and a
+// bare JSON array, which is never paginated. An endpoint that ignores
+// limit/offset is read once. The first error is yielded and ends the iteration.
+func Paginate[T any](ctx context.Context, m *MetabaseAPIClient, path string, pageSize int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if pageSize <= 0 {
+			pageSize = DefaultPageSize
+		}
+
+		seen := 0
+		for offset := 0; ; offset += pageSize {
+			page, err := fetchPage[T](ctx, m, pagePath(path, pageSize, offset))
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			seen += len(page.Data)
+
+			switch {
+			case page.Limit == nil || page.Offset == nil:
+				return // not paginated: everything was returned
+			case len(page.Data) == 0 || len(page.Data) > pageSize:
+				return
+			case page.Total != nil && seen >= *page.Total:
+				return
+			case page.Total == nil && len(page.Data) < pageSize:
+				return
+			}
+		}
+	}
+}
+
+// ListAll collects every item of the listing at path (see Paginate).
+func ListAll[T any](ctx context.Context, m *MetabaseAPIClient, path string) ([]T, error) {
+	var items []T
+	for item, err := range Paginate[T](ctx, m, path, DefaultPageSize) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func pagePath(path string, limit, offset int) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%slimit=%d&offset=%d", path, separator, limit, offset)
+}
+
+// fetchPage decodes one page; a bare array is returned as an unpaginated
+// envelope (nil Limit/Offset).
+func fetchPage[T any](ctx context.Context, m *MetabaseAPIClient, path string) (*pageEnvelope[T], error) {
+	resp, err := m.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read listing %s: %w", path, err)
+	}
+
+	var page pageEnvelope[T]
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &page.Data)
+	} else {
+		err = json.Unmarshal(body, &page)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode listing %s: %w", path, err)
+	}
+	return &page, nil
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type pagedItem struct {
+	Id int `json:"id"`
+}
+
+// fakePagedServer serves total items as a {data, total, limit, offset}
+// listing, honouring limit/offset, and records the requested offsets.
+type fakePagedServer struct {
+	mu      sync.Mutex
+	total   int
+	offsets []int
+	queries []string
+}
+
+func newFakePagedServer(t *testing.T, total int) (*fakePagedServer, *httptest.Server) {
+	t.Helper()
+	f := &fakePagedServer{total: total}
+	srv := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakePagedServer) handle(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	f.mu.Lock()
+	f.offsets = append(f.offsets, offset)
+	f.queries = append(f.queries, r.URL.RawQuery)
+	f.mu.Unlock()
+
+	data := []pagedItem{}
+	for i := offset; i < offset+limit && i < f.total; i++ {
+		data = append(data, pagedItem{Id: i + 1})
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data, "total": f.total, "limit": limit, "offset": offset})
+}
+
+func TestPaginate_WalksEveryPage(t *testing.T) {
+	f, srv := newFakePagedServer(t, 7)
+	client := NewMetabaseAPIClient(srv.URL, "test-key")
+
+	var ids []int
+	for item, err := range Paginate[pagedItem](context.Background(), client, "/api/user?status=all", 3) {
+		if err != nil {
+			t.Fatalf("Paginate failed: %v", err)
+		}
+		ids = append(ids, item.Id)
+	}
+
+	if len(ids) != 7 || ids[0] != 1 || ids[6] != 7 {
+		t.Errorf("expected items 1..7, got %v", ids)
+	}
+	if want := []int{0, 3, 6}; len(f.offsets) != len(want) || f.offsets[0] != 0 || f.offsets[1] != 3 || f.offsets[2] != 6 {
+		t.Errorf("expected offsets %v, got %v", want, f.offsets)
+	}
+	if f.queries[1] != "status=all&limit=3&offset=3" {
+		t.Errorf("expected the existing query to be kept, got %q", f.queries[1])
+	}
+}
+
+func TestPaginate_ExactMultipleOfPageSize(t *testing.T) {
+	f, srv := newFakePagedServer(t, 6)
+	client := NewMetabaseAPIClient(srv.URL, "test-key")
+
+	items, err := collectPaged(client, "/api/user", 3)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if len(items) != 6 {
+		t.Errorf("expected 6 items, got %d", len(items))
+	}
+	// total tells us we're done: no trailing empty page.
+	if len(f.offsets) != 2 {
+		t.Errorf("expected 2 requests, got %d (%v)", len(f.offsets), f.offsets)
+	}
+}
+
+func TestPaginate_StopsEarly(t *testing.T) {
+	f, srv := newFakePagedServer(t, 10)
+	client := NewMetabaseAPIClient(srv.URL, "test-key")
+
+	for item, err := range Paginate[pagedItem](context.Background(), client, "/api/user", 3) {
+		if err != nil {
+			t.Fatalf("Paginate failed: %v", err)
+		}
+		if item.Id == 2 {
+			break
+		}
+	}
+	if len(f.offsets) != 1 {
+		t.Errorf("expected a single request, got %d", len(f.offsets))
+	}
+}
+
+func TestPaginate_ShortPageWithoutTotal(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("offset") == "0" {
+			_, _ = w.Write([]byte(`{"data":[{"id":1},{"id":2}],"limit":2,"offset":0}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":3}],"limit":2,"offset":2}`))
+	}))
+	defer srv.Close()
+	client := NewMetabaseAPIClient(srv.URL, "test-key")
+
+	items, err := collectPaged(client, "/api/database", 2)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if len(items) != 3 || requests != 2 {
+		t.Errorf("expected 3 items in 2 requests, got %d in %d", len(items), requests)
+	}
+}
+
+func TestPaginate_UnpaginatedListings(t *testing.T) {
+	cases := map[string]string{
+		// Endpoints that ignore limit/offset return everything with null echoes...
+		"envelope": `{"data":[{"id":1},{"id":2},{"id":3}],"total":3,"limit":null,"offset":null}`,
+		// ...or a bare array.
+		"bare array": `[{"id":1},{"id":2},{"id":3}]`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				_, _ = w.Write([]byte(body))
+			}))
+			defer srv.Close()
+			client := NewMetabaseAPIClient(srv.URL, "test-key")
+
+			// The page size is smaller than the listing: it must not loop.
+			items, err := collectPaged(client, "/api/collection", 2)
+			if err != nil {
+				t.Fatalf("Paginate failed: %v", err)
+			}
+			if len(items) != 3 || requests != 1 {
+				t.Errorf("expected 3 items in 1 request, got %d in %d", len(items), requests)
+			}
+		})
+	}
+}
+
+func TestPaginate_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "0" {
+			_, _ = w.Write([]byte(`{"data":[{"id":1},{"id":2}],"total":4,"limit":2,"offset":0}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`"You don't have permissions to do that."`))
+	}))
+	defer srv.Close()
+	client := NewMetabaseAPIClient(srv.URL, "test-key")
+
+	items, err := ListAll[pagedItem](context.Background(), client, "/api/user")
+	if err == nil {
+		t.Fatalf("expected an error, got %d items", len(items))
+	}
+	if items != nil {
+		t.Errorf("expected no items on error, got %v", items)
+	}
+}
+
+func collectPaged(client *MetabaseAPIClient, path string, pageSize int) ([]pagedItem, error) {
+	var items []pagedItem
+	for item, err := range Paginate[pagedItem](context.Background(), client, path, pageSize) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+)
+
+// Application permissions are an Enterprise "advanced permissions" feature.
+var applicationGraphRequirement = metabase.Requirement{
+	Feature:      "metabase_application_permission",
+	TokenFeature: "advanced_permissions",
+}
+
+type applicationGraph struct {
+	Revision int                          `json:"revision"`
+	Groups   map[string]map[string]string `json:"groups"`
+}
+
+// ApplicationPermissionRepository manages the application permissions graph:
+// per group, "yes" or "no" access to each area ("setting", "monitoring",
+// "subscription").
+type ApplicationPermissionRepository struct {
+	client *metabase.MetabaseAPIClient
+}
+
+func NewApplicationPermissionRepository(client *metabase.MetabaseAPIClient) *ApplicationPermissionRepository {
+	return &ApplicationPermissionRepository{client: client}
+}
+
+// Supported returns an *metabase.UnsupportedError when the instance has no
+// application permissions.
+func (r *ApplicationPermissionRepository) Supported() error {
+	return r.client.Require(applicationGraphRequirement)
+}
+
+func (r *ApplicationPermissionRepository) get(ctx context.Context) (*applicationGraph, error) {
+	resp, err := r.client.Get(ctx, "/api/ee/advanced-permissions/application/graph")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var g applicationGraph
+	if err := json.NewDecoder(resp.Body).Decode(&g); err != nil {
+		return nil, fmt.Errorf("failed to decode application permissions graph: %w", err)
+	}
+	return &g, nil
+}
+
+// Get reports whether the group has access to the area.
+func (r *ApplicationPermissionRepository) Get(ctx context.Context, groupId string, area string) (bool, error) {
+	if err := r.client.Require(applicationGraphRequirement); err != nil {
+		return false, err
+	}
+	g, err := r.get(ctx)
+	if err != nil {
+		return false, err
+	}
+	return g.Groups[groupId][area] == "yes", nil
+}
+
+// Set grants ("yes") or revokes ("no") the group's access to the area. The
+// read-modify-write is retried on a stale revision.
+func (r *ApplicationPermissionRepository) Set(ctx context.Context, groupId string, area string, access string) error {
+	if err := r.client.Require(applicationGraphRequirement); err != nil {
+		return err
+	}
+
+	// Serialize in-process: the read-modify-write races on the shared revision id.
+	applicationGraphMu.Lock()
+	defer applicationGraphMu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		g, err := r.get(ctx)
+		if err != nil {
+			return err
+		}
+		body := map[string]any{
+			"revision": g.Revision,
+			"groups":   map[string]any{groupId: map[string]any{area: access}},
+		}
+		// Retried here only, after re-reading the revision.
+		_, err = r.client.Put(metabase.WithoutRetries(ctx), "/api/ee/advanced-permissions/application/graph", body)
+		if err == nil {
+			return nil
+		}
+		if attempt < graphMaxAttempts && isRetryableGraphError(err) {
+			if err := r.client.WaitBeforeRetry(ctx, attempt, err); err != nil {
+				return err
+			}
+			continue
+		}
+		return err
+	}
+}
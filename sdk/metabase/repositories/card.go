@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+// Metric cards (type "metric") were introduced in Metabase 51.
+var metricCardRequirement = metabase.Requirement{Feature: "metabase_card with type = \"metric\"", MinVersion: 51}
+
+type CardRepository struct {
+	client *metabase.MetabaseAPIClient
+}
+
+func NewCardRepository(client *metabase.MetabaseAPIClient) *CardRepository {
+	return &CardRepository{client: client}
+}
+
+// cardBody builds the create/update body. collection_id is always sent so
+// that null moves the card to the root collection.
+func cardBody(card *dtos.CardDTO) map[string]any {
+	return map[string]any{
+		"name":                   card.Name,
+		"description":            card.Description,
+		"collection_id":          card.CollectionId,
+		"display":                card.Display,
+		"type":                   card.Type,
+		"dataset_query":          card.DatasetQuery,
+		"visualization_settings": card.VisualizationSettings,
+	}
+}
+
+func (r *CardRepository) Create(ctx context.Context, card *dtos.CardDTO) (*dtos.CardDTO, error) {
+	if card.Type == "metric" {
+		if err := r.client.Require(metricCardRequirement); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := r.client.Post(ctx, "/api/card", cardBody(card))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.CardDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode create response: %w", err)
+	}
+	return &res, nil
+}
+
+func (r *CardRepository) Get(ctx context.Context, id string) (*dtos.CardDTO, error) {
+	path := fmt.Sprintf("/api/card/%s", id)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.CardDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode get response: %w", err)
+	}
+	return &res, nil
+}
+
+func (r *CardRepository) Update(ctx context.Context, id string, card *dtos.CardDTO) (bool, error) {
+	if card.Type == "metric" {
+		if err := r.client.Require(metricCardRequirement); err != nil {
+			return false, err
+		}
+	}
+
+	body := cardBody(card)
+	body["archived"] = card.Archived
+
+	path := fmt.Sprintf("/api/card/%s", id)
+	resp, err := r.client.Put(ctx, path, body)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return true, nil
+}
+
+// Archive sends the card to the Trash (recoverable, like collections).
+// Idempotent on 404.
+func (r *CardRepository) Archive(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/api/card/%s", id)
+	resp, err := r.client.Put(ctx, path, map[string]any{"archived": true})
+	if err != nil {
+		var notFound *metabase.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -9,14 +9,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"time"
+	"strings"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 )
 
 // Collection creation is not concurrency-safe: concurrent creates race on the
-// revision id and 5xx. Retry a few times.
+// revision id and 5xx. Retry a few times; the client never retries a POST on
+// a 5xx, so these are the only retries of that failure.
 const collectionCreateMaxAttempts = 4
 
 type CollectionRepository struct {
@@ -51,7 +52,9 @@ func (r *CollectionRepository) Create(ctx context.Context, name string, parentId
 		}
 		var baseErr *metabase.BaseError
 		if attempt < collectionCreateMaxAttempts && errors.As(err, &baseErr) && baseErr.StatusCode >= 500 {
-			time.Sleep(time.Duration(attempt) * 150 * time.Millisecond)
+			if err := r.client.WaitBeforeRetry(ctx, attempt, err); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		return nil, err
@@ -124,3 +127,62 @@ func (r *CollectionRepository) Archive(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// listedCollectionDTO is an /api/collection item. Id is `any` because the
+// listing includes the virtual root collection with id "root".
+type listedCollectionDTO struct {
+	Id              any    `json:"id"`
+	Name            string `json:"name"`
+	Location        string `json:"location"`
+	Archived        bool   `json:"archived"`
+	PersonalOwnerId *int   `json:"personal_owner_id"`
+}
+
+// List returns every non-archived collection, personal ones included (see
+// PersonalOwnerId); the virtual root collection is skipped.
+func (r *CollectionRepository) List(ctx context.Context) ([]dtos.CollectionDTO, error) {
+	listed, err := metabase.ListAll[listedCollectionDTO](ctx, r.client, "/api/collection")
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]dtos.CollectionDTO, 0, len(listed))
+	for _, c := range listed {
+		id, ok := c.Id.(float64) // skips the virtual root ("root")
+		if !ok || c.Archived {
+			continue
+		}
+		cols = append(cols, dtos.CollectionDTO{Id: int(id), Name: c.Name, Location: c.Location, Archived: c.Archived, PersonalOwnerId: c.PersonalOwnerId})
+	}
+	return cols, nil
+}
+
+// FindByName returns the non-archived, non-personal collection with the given
+// exact name directly under parentId (nil for the top level), or nil if none
+// exists. Sibling collections may share a name: that is an error.
+func (r *CollectionRepository) FindByName(ctx context.Context, name string, parentId *string) (*dtos.CollectionDTO, error) {
+	cols, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []dtos.CollectionDTO
+	for _, c := range cols {
+		if c.PersonalOwnerId != nil || c.Name != name {
+			continue
+		}
+		// location is the ancestor chain, e.g. "/10/24/" for a child of 24.
+		if parentId == nil && c.Location != "/" || parentId != nil && !strings.HasSuffix(c.Location, "/"+*parentId+"/") {
+			continue
+		}
+		found = append(found, c)
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("%d collections named %q share the same parent (ids %d and %d); look it up by id instead", len(found), name, found[0].Id, found[1].Id)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
 )
@@ -62,36 +61,109 @@ func (r *CollectionPermissionRepository) Set(ctx context.Context, groupId string
 // SetMany applies the same permission to every collection in ONE graph PUT
 // (single revision bump, no half-applied subtree). Used by `propagate`.
 func (r *CollectionPermissionRepository) SetMany(ctx context.Context, groupId string, collectionIds []string, permission string) error {
-	// Serialize in-process: the read-modify-write races on the shared revision id.
-	collectionGraphMu.Lock()
-	defer collectionGraphMu.Unlock()
-
 	edges := map[string]any{}
 	for _, id := range collectionIds {
 		edges[id] = permission
 	}
+	return r.putGroup(ctx, groupId, func(*collectionGraph) map[string]any {
+		return edges
+	})
+}
+
+// GetGroup returns the group's permission on every collection it has a grant
+// on ("read" or "write"), keyed by collection id ("root" included). The Trash
+// is skipped: its permissions can't be edited.
+func (r *CollectionPermissionRepository) GetGroup(ctx context.Context, groupId string) (map[string]string, error) {
+	g, err := r.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trashId, err := r.trashId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissions := map[string]string{}
+	for collectionId, perm := range g.Groups[groupId] {
+		if perm != "none" && collectionId != trashId {
+			permissions[collectionId] = perm
+		}
+	}
+	return permissions, nil
+}
+
+// ReplaceGroup makes permissions (collection id → "read"/"write") the group's
+// complete set of grants in one graph PUT: every other collection the group
+// has a grant on is revoked ("none"), computed from the graph read on each
+// attempt. The Trash is never touched.
+func (r *CollectionPermissionRepository) ReplaceGroup(ctx context.Context, groupId string, permissions map[string]string) error {
+	trashId, err := r.trashId(ctx)
+	if err != nil {
+		return err
+	}
+	return r.putGroup(ctx, groupId, func(g *collectionGraph) map[string]any {
+		edges := map[string]any{}
+		for collectionId, perm := range g.Groups[groupId] {
+			if _, ok := permissions[collectionId]; !ok && perm != "none" && collectionId != trashId {
+				edges[collectionId] = "none"
+			}
+		}
+		for collectionId, perm := range permissions {
+			edges[collectionId] = perm
+		}
+		return edges
+	})
+}
+
+// putGroup writes the group's edges returned by edges (called with the current
+// graph on every attempt). Nothing is written when it returns no edges.
+func (r *CollectionPermissionRepository) putGroup(ctx context.Context, groupId string, edges func(g *collectionGraph) map[string]any) error {
+	// Serialize in-process: the read-modify-write races on the shared revision id.
+	collectionGraphMu.Lock()
+	defer collectionGraphMu.Unlock()
 
 	for attempt := 1; ; attempt++ {
 		g, err := r.get(ctx)
 		if err != nil {
 			return err
 		}
+		groupEdges := edges(g)
+		if len(groupEdges) == 0 {
+			return nil
+		}
 		body := map[string]any{
 			"revision": g.Revision,
-			"groups":   map[string]any{groupId: edges},
+			"groups":   map[string]any{groupId: groupEdges},
 		}
-		_, err = r.client.Put(ctx, "/api/collection/graph", body)
+		// Retried here only, after re-reading the revision.
+		_, err = r.client.Put(metabase.WithoutRetries(ctx), "/api/collection/graph", body)
 		if err == nil {
 			return nil
 		}
 		if attempt < graphMaxAttempts && isRetryableGraphError(err) {
-			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+			if err := r.client.WaitBeforeRetry(ctx, attempt, err); err != nil {
+				return err
+			}
 			continue
 		}
 		return err
 	}
 }
 
+// trashId returns the id of the Trash collection, or "" when there is none
+// (before Metabase 50).
+func (r *CollectionPermissionRepository) trashId(ctx context.Context) (string, error) {
+	cols, err := r.list(ctx, true)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range cols {
+		if id, ok := c.Id.(float64); ok && c.Type == "trash" {
+			return strconv.Itoa(int(id)), nil
+		}
+	}
+	return "", nil
+}
+
 // listedCollection is the subset of /api/collection items we need. Id is `any`
 // because the listing includes the virtual root collection with id "root".
 type listedCollection struct {
@@ -106,16 +178,7 @@ func (r *CollectionPermissionRepository) list(ctx context.Context, archived bool
 	if archived {
 		path += "?archived=true"
 	}
-	resp, err := r.client.Get(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	var cols []listedCollection
-	if err := json.NewDecoder(resp.Body).Decode(&cols); err != nil {
-		return nil, fmt.Errorf("failed to decode collection list: %w", err)
-	}
-	return cols, nil
+	return metabase.ListAll[listedCollection](ctx, r.client, path)
 }
 
 // ListDescendants returns the ids of every collection under collectionId
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+// Impersonation is an Enterprise "advanced permissions" feature; policies are
+// written through the data graph (Metabase 50 or later).
+var connectionImpersonationRequirement = metabase.Requirement{
+	Feature:      "metabase_connection_impersonation",
+	MinVersion:   50,
+	TokenFeature: "advanced_permissions",
+}
+
+// ConnectionImpersonationRepository manages connection impersonation policies
+// (/api/ee/advanced-permissions/impersonation). Writes go through the data
+// permissions graph, so the database's view-data changes with the policy.
+type ConnectionImpersonationRepository struct {
+	client *metabase.MetabaseAPIClient
+	graph  *DatabasePermissionRepository
+}
+
+func NewConnectionImpersonationRepository(client *metabase.MetabaseAPIClient) *ConnectionImpersonationRepository {
+	return &ConnectionImpersonationRepository{client: client, graph: NewDatabasePermissionRepository(client)}
+}
+
+// Supported returns an *metabase.UnsupportedError when the instance has no
+// connection impersonation.
+func (r *ConnectionImpersonationRepository) Supported() error {
+	return r.client.Require(connectionImpersonationRequirement)
+}
+
+// Find returns the group's policy on the database, or nil when there is none.
+func (r *ConnectionImpersonationRepository) Find(ctx context.Context, groupId string, databaseId string) (*dtos.ConnectionImpersonationDTO, error) {
+	if err := r.client.Require(connectionImpersonationRequirement); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/ee/advanced-permissions/impersonation?group_id=%s&db_id=%s", groupId, databaseId)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		var notFound *metabase.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// With both ids the API returns a single policy (null for none).
+	var res *dtos.ConnectionImpersonationDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode find response: %w", err)
+	}
+	return res, nil
+}
+
+// Set creates or updates the group's policy on the database and sets its
+// view-data to "impersonated" in the same graph write.
+func (r *ConnectionImpersonationRepository) Set(ctx context.Context, groupId string, databaseId string, attribute string) error {
+	if err := r.client.Require(connectionImpersonationRequirement); err != nil {
+		return err
+	}
+	groupIdInt, err := strconv.Atoi(groupId)
+	if err != nil {
+		return fmt.Errorf("invalid group id %q", groupId)
+	}
+	databaseIdInt, err := strconv.Atoi(databaseId)
+	if err != nil {
+		return fmt.Errorf("invalid database id %q", databaseId)
+	}
+	impersonation := map[string]any{"group_id": groupIdInt, "db_id": databaseIdInt, "attribute": attribute}
+	return r.setViewData(ctx, groupId, databaseId, "impersonated", map[string]any{"impersonations": []any{impersonation}})
+}
+
+// Delete sets the database's view-data for the group back to viewData (which
+// drops the policy in Metabase) and deletes the policy if it is left.
+func (r *ConnectionImpersonationRepository) Delete(ctx context.Context, groupId string, databaseId string, viewData string) error {
+	if err := r.setViewData(ctx, groupId, databaseId, viewData, nil); err != nil {
+		return err
+	}
+	impersonation, err := r.Find(ctx, groupId, databaseId)
+	if err != nil || impersonation == nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/api/ee/advanced-permissions/impersonation/%d", impersonation.Id)
+	resp, err := r.client.Delete(ctx, path)
+	if err != nil {
+		// Idempotent delete: if the policy is already gone, treat as success.
+		var notFound *metabase.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ViewData returns the group's database-wide view-data level; "blocked"
+// (Metabase's default) when the graph has none or it is granular per table.
+func (r *ConnectionImpersonationRepository) ViewData(ctx context.Context, groupId string, databaseId string) (string, error) {
+	if err := r.client.Require(connectionImpersonationRequirement); err != nil {
+		return "", err
+	}
+	g, err := r.graph.get(ctx)
+	if err != nil {
+		return "", err
+	}
+	if level, ok := g.Groups[groupId][databaseId]["view-data"].(string); ok {
+		return level, nil
+	}
+	return "blocked", nil
+}
+
+// HasTableViewData reports whether the group's view-data on the database is
+// set per table (e.g. with sandboxed tables) or "sandboxed": the
+// database-wide "impersonated" would replace it.
+func (r *ConnectionImpersonationRepository) HasTableViewData(ctx context.Context, groupId string, databaseId string) (bool, error) {
+	if err := r.client.Require(connectionImpersonationRequirement); err != nil {
+		return false, err
+	}
+	g, err := r.graph.get(ctx)
+	if err != nil {
+		return false, err
+	}
+	switch v := g.Groups[groupId][databaseId]["view-data"].(type) {
+	case map[string]any:
+		return true, nil
+	case string:
+		return v == "sandboxed", nil
+	}
+	return false, nil
+}
+
+// setViewData writes the group's database-wide view-data level, with extra
+// keys in the graph PUT.
+func (r *ConnectionImpersonationRepository) setViewData(ctx context.Context, groupId string, databaseId string, level string, extra map[string]any) error {
+	return r.graph.putGraph(ctx, groupId, func(*dataGraph) map[string]any {
+		return map[string]any{databaseId: map[string]any{"view-data": level}}
+	}, extra)
+}
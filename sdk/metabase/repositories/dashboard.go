@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+// Tabs, and writing dashcards through PUT /api/dashboard/:id, were introduced
+// in Metabase 47.
+var dashboardLayoutRequirement = metabase.Requirement{Feature: "metabase_dashboard tabs and dashcards", MinVersion: 47}
+
+type DashboardRepository struct {
+	client *metabase.MetabaseAPIClient
+}
+
+func NewDashboardRepository(client *metabase.MetabaseAPIClient) *DashboardRepository {
+	return &DashboardRepository{client: client}
+}
+
+// parametersOrEmpty sends Metabase's default [] for unset parameters.
+func parametersOrEmpty(parameters json.RawMessage) json.RawMessage {
+	if len(parameters) == 0 {
+		return json.RawMessage("[]")
+	}
+	return parameters
+}
+
+// Create creates an empty dashboard; tabs and dashcards are set with Update.
+func (r *DashboardRepository) Create(ctx context.Context, dashboard *dtos.DashboardDTO) (*dtos.DashboardDTO, error) {
+	body := map[string]any{
+		"name":          dashboard.Name,
+		"description":   dashboard.Description,
+		"collection_id": dashboard.CollectionId,
+		"parameters":    parametersOrEmpty(dashboard.Parameters),
+	}
+
+	resp, err := r.client.Post(ctx, "/api/dashboard", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.DashboardDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode create response: %w", err)
+	}
+	return &res, nil
+}
+
+func (r *DashboardRepository) Get(ctx context.Context, id string) (*dtos.DashboardDTO, error) {
+	path := fmt.Sprintf("/api/dashboard/%s", id)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.DashboardDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode get response: %w", err)
+	}
+	return &res, nil
+}
+
+// Update replaces the whole dashboard in one PUT: tabs and dashcards are the
+// complete new lists (see dtos.DashcardDTO for ids). collection_id is always
+// sent so that null moves the dashboard to the root collection.
+func (r *DashboardRepository) Update(ctx context.Context, id string, dashboard *dtos.DashboardDTO) (bool, error) {
+	if len(dashboard.Tabs) > 0 || len(dashboard.Dashcards) > 0 {
+		if err := r.client.Require(dashboardLayoutRequirement); err != nil {
+			return false, err
+		}
+	}
+
+	tabs := dashboard.Tabs
+	if tabs == nil {
+		tabs = []dtos.DashboardTabDTO{}
+	}
+	dashcards := dashboard.Dashcards
+	if dashcards == nil {
+		dashcards = []dtos.DashcardDTO{}
+	}
+	body := map[string]any{
+		"name":          dashboard.Name,
+		"description":   dashboard.Description,
+		"collection_id": dashboard.CollectionId,
+		"parameters":    parametersOrEmpty(dashboard.Parameters),
+		"tabs":          tabs,
+		"dashcards":     dashcards,
+	}
+
+	path := fmt.Sprintf("/api/dashboard/%s", id)
+	resp, err := r.client.Put(ctx, path, body)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return true, nil
+}
+
+// Archive sends the dashboard to the Trash (recoverable, like cards).
+// Idempotent on 404.
+func (r *DashboardRepository) Archive(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/api/dashboard/%s", id)
+	resp, err := r.client.Put(ctx, path, map[string]any{"archived": true})
+	if err != nil {
+		var notFound *metabase.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
@@ -29,7 +31,56 @@ func decodeDetails(detailsJSON string) (map[string]any, error) {
 	return details, nil
 }
 
-func (r *DatabaseRepository) Create(ctx context.Context, name string, engine string, detailsJSON string) (*dtos.DatabaseDTO, error) {
+// cache_ttl on a database is an Enterprise granular caching control.
+var databaseCacheTTLRequirement = metabase.Requirement{
+	Feature:      "metabase_database cache_ttl",
+	TokenFeature: "cache_granular_controls",
+}
+
+// optionsBody writes the managed options into body. refingerprint and
+// settings are only accepted on update: they are skipped unless update is set.
+func (r *DatabaseRepository) optionsBody(body map[string]any, options dtos.DatabaseOptionsDTO, update bool) error {
+	schedules := map[string]any{}
+	for key, cron := range map[string]*string{"metadata_sync": options.MetadataSyncSchedule, "cache_field_values": options.CacheFieldValuesSchedule} {
+		if cron == nil {
+			continue
+		}
+		schedule, err := metabase.ParseCronSchedule(*cron)
+		if err != nil {
+			return err
+		}
+		schedules[key] = schedule.Map()
+	}
+	if len(schedules) > 0 {
+		body["schedules"] = schedules
+	}
+	if options.IsFullSync != nil {
+		body["is_full_sync"] = *options.IsFullSync
+	}
+	if options.IsOnDemand != nil {
+		body["is_on_demand"] = *options.IsOnDemand
+	}
+	if options.AutoRunQueries != nil {
+		body["auto_run_queries"] = *options.AutoRunQueries
+	}
+	if options.CacheTTL != nil {
+		if err := r.client.Require(databaseCacheTTLRequirement); err != nil {
+			return err
+		}
+		body["cache_ttl"] = *options.CacheTTL
+	}
+	if update && options.Refingerprint != nil {
+		body["refingerprint"] = *options.Refingerprint
+	}
+	if update && options.Settings != nil {
+		body["settings"] = options.Settings
+	}
+	return nil
+}
+
+// Create creates the database. When a step after the creation fails, the
+// created database is returned along with the error.
+func (r *DatabaseRepository) Create(ctx context.Context, name string, engine string, detailsJSON string, options dtos.DatabaseOptionsDTO) (*dtos.DatabaseDTO, error) {
 	// Bound concurrency: each create runs a heavy connection test + schema sync.
 	acquireDatabaseWrite()
 	defer releaseDatabaseWrite()
@@ -39,6 +90,9 @@ func (r *DatabaseRepository) Create(ctx context.Context, name string, engine str
 		return nil, err
 	}
 	body := map[string]any{"name": name, "engine": engine, "details": details}
+	if err := r.optionsBody(body, options, false); err != nil {
+		return nil, err
+	}
 
 	resp, err := r.client.Post(ctx, "/api/database", body)
 	if err != nil {
@@ -50,7 +104,21 @@ func (r *DatabaseRepository) Create(ctx context.Context, name string, engine str
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, fmt.Errorf("failed to decode create response: %w", err)
 	}
-	return &res, nil
+	if options.Refingerprint == nil && options.Settings == nil {
+		return &res, nil
+	}
+
+	// The create endpoint ignores refingerprint and settings: set them now. The
+	// database exists already, so it is returned along with a failure.
+	updateBody := map[string]any{}
+	if err := r.optionsBody(updateBody, dtos.DatabaseOptionsDTO{Refingerprint: options.Refingerprint, Settings: options.Settings}, true); err != nil {
+		return &res, err
+	}
+	updated, err := r.put(ctx, strconv.Itoa(res.Id), updateBody)
+	if err != nil {
+		return &res, fmt.Errorf("database %d was created, but setting refingerprint and settings failed: %w", res.Id, err)
+	}
+	return updated, nil
 }
 
 func (r *DatabaseRepository) Get(ctx context.Context, id string) (*dtos.DatabaseDTO, error) {
@@ -68,7 +136,36 @@ func (r *DatabaseRepository) Get(ctx context.Context, id string) (*dtos.Database
 	return &res, nil
 }
 
-func (r *DatabaseRepository) Update(ctx context.Context, id string, name *string, detailsJSON *string) (bool, error) {
+// List returns every database connection (connection details included).
+func (r *DatabaseRepository) List(ctx context.Context) ([]dtos.DatabaseDTO, error) {
+	return metabase.ListAll[dtos.DatabaseDTO](ctx, r.client, "/api/database")
+}
+
+// FindByName returns the database with the given exact name, or nil if none
+// exists. Names are not unique in Metabase: several matches are an error.
+func (r *DatabaseRepository) FindByName(ctx context.Context, name string) (*dtos.DatabaseDTO, error) {
+	databases, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []dtos.DatabaseDTO
+	for _, db := range databases {
+		if db.Name == name {
+			found = append(found, db)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("%d databases are named %q (ids %d and %d); look it up by id instead", len(found), name, found[0].Id, found[1].Id)
+	}
+}
+
+func (r *DatabaseRepository) Update(ctx context.Context, id string, name *string, detailsJSON *string, options dtos.DatabaseOptionsDTO) (*dtos.DatabaseDTO, error) {
 	acquireDatabaseWrite()
 	defer releaseDatabaseWrite()
 
@@ -79,19 +176,117 @@ func (r *DatabaseRepository) Update(ctx context.Context, id string, name *string
 	if detailsJSON != nil {
 		details, err := decodeDetails(*detailsJSON)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 		body["details"] = details
 	}
+	if err := r.optionsBody(body, options, true); err != nil {
+		return nil, err
+	}
+	return r.put(ctx, id, body)
+}
 
+func (r *DatabaseRepository) put(ctx context.Context, id string, body map[string]any) (*dtos.DatabaseDTO, error) {
 	path := fmt.Sprintf("/api/database/%s", id)
 	resp, err := r.client.Put(ctx, path, body)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.DatabaseDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode update response: %w", err)
+	}
+	return &res, nil
+}
+
+// ValidateConnection tests connection details without saving anything. A
+// failed connection is not an error: it is reported in the result, with the
+// driver's message.
+func (r *DatabaseRepository) ValidateConnection(ctx context.Context, engine string, detailsJSON string) (*dtos.ConnectionValidationDTO, error) {
+	// Bound concurrency like writes: it runs the same connection test.
+	acquireDatabaseWrite()
+	defer releaseDatabaseWrite()
+
+	details, err := decodeDetails(detailsJSON)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{"details": map[string]any{"engine": engine, "details": details}}
+
+	resp, err := r.client.Post(ctx, "/api/database/validate", body)
+	if err != nil {
+		// Depending on the version, a failed connection is a 400 carrying the
+		// same {valid, message} body.
+		var badRequest *metabase.BadRequestError
+		if errors.As(err, &badRequest) {
+			var res dtos.ConnectionValidationDTO
+			if json.Unmarshal([]byte(badRequest.Message), &res) == nil && res.Message != "" {
+				return &res, nil
+			}
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.ConnectionValidationDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode validate response: %w", err)
+	}
+	if !res.Valid && res.Message == "" {
+		res.Message = "Metabase could not connect with these details"
+	}
+	return &res, nil
+}
+
+// SyncSchema starts a metadata sync of the database. Metabase runs it in the
+// background: the call returns before the sync is done.
+func (r *DatabaseRepository) SyncSchema(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/api/database/%s/sync_schema", id)
+	resp, err := r.client.Post(ctx, path, nil)
+	if err != nil {
+		return err
 	}
 	defer resp.Body.Close()
 
-	return true, nil
+	return nil
+}
+
+// SyncAbortedError is returned by WaitForInitialSync when Metabase aborted
+// the database's initial sync.
+type SyncAbortedError struct {
+	DatabaseId string
+}
+
+func (e *SyncAbortedError) Error() string {
+	return fmt.Sprintf("the initial sync of database %s was aborted; check the Metabase logs", e.DatabaseId)
+}
+
+// WaitForInitialSync polls the database every interval until its initial
+// sync is complete, and fails with a SyncAbortedError when the sync was
+// aborted. It returns ctx's error when ctx ends first.
+func (r *DatabaseRepository) WaitForInitialSync(ctx context.Context, id string, interval time.Duration) (*dtos.DatabaseDTO, error) {
+	for {
+		database, err := r.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch database.InitialSyncStatus {
+		case "complete":
+			return database, nil
+		case "aborted":
+			return nil, &SyncAbortedError{DatabaseId: id}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
 }
 
 func (r *DatabaseRepository) Delete(ctx context.Context, id string) error {
@@ -8,15 +8,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
+	"maps"
+	"net/http"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 )
 
 // The permissions graph uses optimistic locking via a revision (stale → 409);
 // writes are read-modify-write with bounded retry.
 const graphMaxAttempts = 5
 
+// The data graph's "create-queries"/"view-data" shape was introduced in
+// Metabase 50; older releases use "data"/"native" keys.
+var dataGraphRequirement = metabase.Requirement{Feature: "metabase_database_permission", MinVersion: 50}
+
+// view-data restrictions, download, data-model and details access are
+// Enterprise "advanced permissions".
+var advancedPermissionsRequirement = metabase.Requirement{
+	Feature:      "metabase_database_permission view_data, download, data_model and details",
+	MinVersion:   50,
+	TokenFeature: "advanced_permissions",
+}
+
 type dataGraph struct {
 	Revision int                                  `json:"revision"`
 	Groups   map[string]map[string]map[string]any `json:"groups"`
@@ -43,28 +57,124 @@ func (r *DatabasePermissionRepository) get(ctx context.Context) (*dataGraph, err
 	return &g, nil
 }
 
-// Get returns the create_queries level for a group/database edge; found is false
-// when there is no entry.
-func (r *DatabasePermissionRepository) Get(ctx context.Context, groupId string, databaseId string) (createQueries string, found bool, err error) {
+// Get returns a group/database edge; found is false when there is no entry.
+func (r *DatabasePermissionRepository) Get(ctx context.Context, groupId string, databaseId string) (permission *dtos.DatabasePermissionDTO, found bool, err error) {
+	if err := r.client.Require(dataGraphRequirement); err != nil {
+		return nil, false, err
+	}
 	g, err := r.get(ctx)
 	if err != nil {
-		return "", false, err
+		return nil, false, err
 	}
 	entry, ok := g.Groups[groupId][databaseId]
 	if !ok {
-		return "", false, nil
+		return nil, false, nil
 	}
-	return createQueriesToString(entry["create-queries"]), true, nil
+	return databasePermissionFromEntry(entry), true, nil
 }
 
-// Set applies create_queries for the group/database edge. view-data is omitted:
-// on OSS it is always "unrestricted" and Metabase leaves it untouched when absent.
-func (r *DatabasePermissionRepository) Set(ctx context.Context, groupId string, databaseId string, createQueries string) error {
-	entry := map[string]any{"create-queries": createQueries}
+// IsPolicyViewData reports whether a view-data level is set along with a
+// policy ("sandboxed" by a sandbox, "impersonated" by a connection
+// impersonation) rather than written directly.
+func IsPolicyViewData(viewData string) bool {
+	return viewData == "sandboxed" || viewData == "impersonated"
+}
+
+// Set writes every managed field of the group/database edge in one PUT. Unset
+// fields are omitted and left untouched (view-data is always "unrestricted" on
+// OSS); policy view-data levels (see IsPolicyViewData) are never written.
+func (r *DatabasePermissionRepository) Set(ctx context.Context, groupId string, databaseId string, permission dtos.DatabasePermissionDTO) error {
+	entry := map[string]any{"create-queries": permission.CreateQueries}
+	if permission.CreateQueriesSchemas != nil {
+		schemas := map[string]any{}
+		for schema, p := range permission.CreateQueriesSchemas {
+			if p.Tables != nil {
+				schemas[schema] = p.Tables
+			} else {
+				schemas[schema] = p.Level
+			}
+		}
+		entry["create-queries"] = schemas
+	}
+	if permission.ViewData != nil && !IsPolicyViewData(*permission.ViewData) {
+		entry["view-data"] = *permission.ViewData
+	}
+	if permission.Download != nil {
+		entry["download"] = map[string]any{"schemas": *permission.Download}
+	} else if len(permission.DownloadSchemas) > 0 {
+		entry["download"] = map[string]any{"schemas": permission.DownloadSchemas}
+	}
+	if permission.DataModel != nil {
+		entry["data-model"] = map[string]any{"schemas": *permission.DataModel}
+	}
+	if permission.Details != nil {
+		entry["details"] = *permission.Details
+	}
+	if len(entry) > 1 {
+		if err := r.client.Require(advancedPermissionsRequirement); err != nil {
+			return err
+		}
+	}
 	return r.putEdge(ctx, groupId, databaseId, entry)
 }
 
 func (r *DatabasePermissionRepository) putEdge(ctx context.Context, groupId string, databaseId string, entry map[string]any) error {
+	return r.putGroup(ctx, groupId, func(*dataGraph) map[string]any {
+		return map[string]any{databaseId: entry}
+	})
+}
+
+// GetGroup returns the create_queries level of every database the group has a
+// grant on (levels other than "no").
+func (r *DatabasePermissionRepository) GetGroup(ctx context.Context, groupId string) (map[string]string, error) {
+	if err := r.client.Require(dataGraphRequirement); err != nil {
+		return nil, err
+	}
+	g, err := r.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	levels := map[string]string{}
+	for databaseId, entry := range g.Groups[groupId] {
+		if level := createQueriesToString(entry["create-queries"]); level != "no" {
+			levels[databaseId] = level
+		}
+	}
+	return levels, nil
+}
+
+// ReplaceGroup makes levels (database id → create_queries) the group's
+// complete set of grants in one graph PUT: every other database the group has
+// a grant on is revoked ("no"). The revocations are computed from the graph
+// read on each attempt, so a grant made concurrently is revoked too.
+func (r *DatabasePermissionRepository) ReplaceGroup(ctx context.Context, groupId string, levels map[string]string) error {
+	return r.putGroup(ctx, groupId, func(g *dataGraph) map[string]any {
+		edges := map[string]any{}
+		for databaseId, entry := range g.Groups[groupId] {
+			if _, ok := levels[databaseId]; !ok && createQueriesToString(entry["create-queries"]) != "no" {
+				edges[databaseId] = map[string]any{"create-queries": "no"}
+			}
+		}
+		for databaseId, level := range levels {
+			edges[databaseId] = map[string]any{"create-queries": level}
+		}
+		return edges
+	})
+}
+
+// putGroup writes the group's edges returned by edges (called with the current
+// graph on every attempt). Nothing is written when it returns no edges.
+func (r *DatabasePermissionRepository) putGroup(ctx context.Context, groupId string, edges func(g *dataGraph) map[string]any) error {
+	return r.putGraph(ctx, groupId, edges, nil)
+}
+
+// putGraph is putGroup with extra top-level keys in the PUT body, e.g. the
+// "sandboxes" written along with the edges.
+func (r *DatabasePermissionRepository) putGraph(ctx context.Context, groupId string, edges func(g *dataGraph) map[string]any, extra map[string]any) error {
+	if err := r.client.Require(dataGraphRequirement); err != nil {
+		return err
+	}
+
 	// Serialize in-process: the read-modify-write races on the shared revision id.
 	permissionsGraphMu.Lock()
 	defer permissionsGraphMu.Unlock()
@@ -74,16 +184,24 @@ func (r *DatabasePermissionRepository) putEdge(ctx context.Context, groupId stri
 		if err != nil {
 			return err
 		}
+		groupEdges := edges(g)
+		if len(groupEdges) == 0 {
+			return nil
+		}
 		body := map[string]any{
 			"revision": g.Revision,
-			"groups":   map[string]any{groupId: map[string]any{databaseId: entry}},
+			"groups":   map[string]any{groupId: groupEdges},
 		}
-		_, err = r.client.Put(ctx, "/api/permissions/graph", body)
+		maps.Copy(body, extra)
+		// Retried here only, after re-reading the revision.
+		_, err = r.client.Put(metabase.WithoutRetries(ctx), "/api/permissions/graph", body)
 		if err == nil {
 			return nil
 		}
 		if attempt < graphMaxAttempts && isRetryableGraphError(err) {
-			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+			if err := r.client.WaitBeforeRetry(ctx, attempt, err); err != nil {
+				return err
+			}
 			continue
 		}
 		return err
@@ -91,14 +209,16 @@ func (r *DatabasePermissionRepository) putEdge(ctx context.Context, groupId stri
 }
 
 // isRetryableGraphError reports whether a graph write is worth retrying: a 409
-// (stale revision) or a 5xx (concurrent writes race on the app-computed revision id).
+// (stale revision), a 429, or a 5xx (concurrent writes race on the
+// app-computed revision id). The client does not retry these writes itself
+// (see metabase.WithoutRetries).
 func isRetryableGraphError(err error) bool {
 	var conflict *metabase.ConflictError
 	if errors.As(err, &conflict) {
 		return true
 	}
 	var base *metabase.BaseError
-	if errors.As(err, &base) && base.StatusCode >= 500 {
+	if errors.As(err, &base) && (base.StatusCode == http.StatusTooManyRequests || base.StatusCode >= 500) {
 		return true
 	}
 	return false
@@ -117,3 +237,94 @@ func createQueriesToString(v any) string {
 		return string(b)
 	}
 }
+
+// databasePermissionFromEntry parses a graph entry, e.g.
+// {"view-data": "unrestricted", "create-queries": "query-builder",
+// "download": {"schemas": "full"}, "data-model": {"schemas": "all"}, "details": "no"}.
+func databasePermissionFromEntry(entry map[string]any) *dtos.DatabasePermissionDTO {
+	permission := &dtos.DatabasePermissionDTO{CreateQueries: createQueriesToString(entry["create-queries"])}
+	if schemas, ok := entry["create-queries"].(map[string]any); ok {
+		permission.CreateQueries = "granular"
+		permission.CreateQueriesSchemas = map[string]dtos.SchemaPermissionDTO{}
+		for schema, v := range schemas {
+			tables, ok := v.(map[string]any)
+			if !ok {
+				permission.CreateQueriesSchemas[schema] = dtos.SchemaPermissionDTO{Level: permissionValueToString(v)}
+				continue
+			}
+			p := dtos.SchemaPermissionDTO{Tables: map[string]string{}}
+			for tableId, level := range tables {
+				p.Tables[tableId] = permissionValueToString(level)
+			}
+			permission.CreateQueriesSchemas[schema] = p
+		}
+	}
+	if v, ok := entry["view-data"]; ok && v != nil {
+		viewData := viewDataToString(v)
+		permission.ViewData = &viewData
+	}
+	switch schemas := schemasOf(entry["download"]).(type) {
+	case nil:
+	case map[string]any:
+		permission.DownloadSchemas = map[string]string{}
+		for schema, level := range schemas {
+			permission.DownloadSchemas[schema] = permissionValueToString(level)
+		}
+	default:
+		download := permissionValueToString(schemas)
+		permission.Download = &download
+	}
+	if schemas := schemasOf(entry["data-model"]); schemas != nil {
+		dataModel := permissionValueToString(schemas)
+		permission.DataModel = &dataModel
+	}
+	if v, ok := entry["details"]; ok && v != nil {
+		details := permissionValueToString(v)
+		permission.Details = &details
+	}
+	return permission
+}
+
+// schemasOf returns the "schemas" value of a {"schemas": ...} permission, or nil.
+func schemasOf(v any) any {
+	if m, ok := v.(map[string]any); ok {
+		return m["schemas"]
+	}
+	return nil
+}
+
+// viewDataToString normalizes a view-data value: a database-level string as-is,
+// a per-table object with a sandboxed table → "sandboxed", any other granular
+// object → its JSON encoding.
+func viewDataToString(v any) string {
+	if granular, ok := v.(map[string]any); ok && hasLeaf(granular, "sandboxed") {
+		return "sandboxed"
+	}
+	return permissionValueToString(v)
+}
+
+func hasLeaf(tree map[string]any, value string) bool {
+	for _, v := range tree {
+		switch t := v.(type) {
+		case string:
+			if t == value {
+				return true
+			}
+		case map[string]any:
+			if hasLeaf(t, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// permissionValueToString returns a string level as-is and a granular object
+// as its JSON encoding.
+func permissionValueToString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+// Fields, like tables, are created by Metabase's schema sync, never through
+// the API.
+type FieldRepository struct {
+	client *metabase.MetabaseAPIClient
+}
+
+func NewFieldRepository(client *metabase.MetabaseAPIClient) *FieldRepository {
+	return &FieldRepository{client: client}
+}
+
+func (r *FieldRepository) Get(ctx context.Context, id string) (*dtos.FieldDTO, error) {
+	path := fmt.Sprintf("/api/field/%s", id)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.FieldDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode get response: %w", err)
+	}
+	return &res, nil
+}
+
+// FindByName returns the active top-level field (column) of a table with the
+// given name, or nil if none exists.
+func (r *FieldRepository) FindByName(ctx context.Context, tableId string, name string) (*dtos.FieldDTO, error) {
+	path := fmt.Sprintf("/api/table/%s/query_metadata", tableId)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var table struct {
+		Fields []dtos.FieldDTO `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&table); err != nil {
+		return nil, fmt.Errorf("failed to decode table fields: %w", err)
+	}
+	for i, f := range table.Fields {
+		if f.Name == name && f.ParentId == nil && f.Active {
+			return &table.Fields[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateMetadata writes the field's curated metadata and returns the field.
+func (r *FieldRepository) UpdateMetadata(ctx context.Context, id string, metadata dtos.FieldMetadataDTO) (*dtos.FieldDTO, error) {
+	body := map[string]any{}
+	if metadata.DisplayName != nil {
+		body["display_name"] = *metadata.DisplayName
+	}
+	if metadata.Description != nil {
+		body["description"] = nilIfEmpty(*metadata.Description)
+	}
+	if metadata.SemanticType != nil {
+		body["semantic_type"] = nilIfEmpty(*metadata.SemanticType)
+	}
+	if metadata.VisibilityType != nil {
+		body["visibility_type"] = *metadata.VisibilityType
+	}
+	if metadata.FkTargetFieldId != nil {
+		if *metadata.FkTargetFieldId == "" {
+			body["fk_target_field_id"] = nil
+		} else {
+			targetId, err := strconv.Atoi(*metadata.FkTargetFieldId)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fk_target_field_id %q: must be a field id", *metadata.FkTargetFieldId)
+			}
+			body["fk_target_field_id"] = targetId
+		}
+	}
+
+	path := fmt.Sprintf("/api/field/%s", id)
+	resp, err := r.client.Put(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.FieldDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode update response: %w", err)
+	}
+	return &res, nil
+}
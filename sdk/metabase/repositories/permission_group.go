@@ -43,18 +43,18 @@ func (r *PermissionGroupRepository) Create(ctx context.Context, name string) (*d
 	return &res, nil
 }
 
+// List returns every permission group, including the built-in "All Users"
+// and "Administrators".
+func (r *PermissionGroupRepository) List(ctx context.Context) ([]dtos.PermissionGroupDTO, error) {
+	return metabase.ListAll[dtos.PermissionGroupDTO](ctx, r.client, "/api/permissions/group")
+}
+
 // FindByName returns the group with the given exact name, or nil if none exists.
 func (r *PermissionGroupRepository) FindByName(ctx context.Context, name string) (*dtos.PermissionGroupDTO, error) {
-	resp, err := r.client.Get(ctx, "/api/permissions/group")
+	groups, err := r.List(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var groups []dtos.PermissionGroupDTO
-	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
-		return nil, fmt.Errorf("failed to decode group list: %w", err)
-	}
 	for i := range groups {
 		if groups[i].Name == name {
 			return &groups[i], nil
@@ -0,0 +1,289 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+// Sandboxes are an Enterprise feature; they are written through the data
+// graph (Metabase 50 or later).
+var sandboxRequirement = metabase.Requirement{Feature: "metabase_sandbox", MinVersion: 50, TokenFeature: "sandboxes"}
+
+type sandbox struct {
+	Id                  int            `json:"id"`
+	GroupId             int            `json:"group_id"`
+	TableId             int            `json:"table_id"`
+	CardId              *int           `json:"card_id"`
+	AttributeRemappings map[string]any `json:"attribute_remappings"`
+}
+
+// SandboxRepository manages sandboxes (/api/mt/gtap). Writes go through the
+// data permissions graph, so the table's view-data changes with the sandbox.
+type SandboxRepository struct {
+	client *metabase.MetabaseAPIClient
+	graph  *DatabasePermissionRepository
+	tables *TableRepository
+}
+
+func NewSandboxRepository(client *metabase.MetabaseAPIClient) *SandboxRepository {
+	return &SandboxRepository{
+		client: client,
+		graph:  NewDatabasePermissionRepository(client),
+		tables: NewTableRepository(client),
+	}
+}
+
+// Supported returns an *metabase.UnsupportedError when the instance has no
+// sandboxes.
+func (r *SandboxRepository) Supported() error {
+	return r.client.Require(sandboxRequirement)
+}
+
+func (r *SandboxRepository) Get(ctx context.Context, id string) (*dtos.SandboxDTO, error) {
+	if err := r.client.Require(sandboxRequirement); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/mt/gtap/%s", id)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res sandbox
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode get response: %w", err)
+	}
+	return sandboxFromAPI(res), nil
+}
+
+// Find returns the group's sandbox of the table, or nil when there is none.
+func (r *SandboxRepository) Find(ctx context.Context, groupId int, tableId int) (*dtos.SandboxDTO, error) {
+	if err := r.client.Require(sandboxRequirement); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/mt/gtap?group_id=%d&table_id=%d", groupId, tableId)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// With both ids the API returns a single sandbox (null for none).
+	var res *sandbox
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode find response: %w", err)
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return sandboxFromAPI(*res), nil
+}
+
+// Set creates the group's sandbox of the table, or updates it when
+// sandbox.Id is set, and sets the table's view-data to "sandboxed" in the
+// same graph write. It returns the sandbox as stored.
+func (r *SandboxRepository) Set(ctx context.Context, s dtos.SandboxDTO) (*dtos.SandboxDTO, error) {
+	if err := r.client.Require(sandboxRequirement); err != nil {
+		return nil, err
+	}
+
+	remappings := make(map[string]any, len(s.AttributeRemappings))
+	for attribute, target := range s.AttributeRemappings {
+		remappings[attribute] = sandboxTargetToAPI(target)
+	}
+	body := map[string]any{
+		"group_id":             s.GroupId,
+		"table_id":             s.TableId,
+		"card_id":              s.CardId,
+		"attribute_remappings": remappings,
+	}
+	if s.Id != 0 {
+		body["id"] = s.Id
+	}
+	if err := r.setViewData(ctx, s.GroupId, s.TableId, "sandboxed", map[string]any{"sandboxes": []any{body}}); err != nil {
+		return nil, err
+	}
+
+	created, err := r.Find(ctx, s.GroupId, s.TableId)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, fmt.Errorf("sandbox of table %d for group %d not found after the write", s.TableId, s.GroupId)
+	}
+	return created, nil
+}
+
+// Delete sets the table's view-data for the group back to viewData (which
+// drops the sandbox in Metabase) and deletes the sandbox if it is left.
+func (r *SandboxRepository) Delete(ctx context.Context, s dtos.SandboxDTO, viewData string) error {
+	if err := r.client.Require(sandboxRequirement); err != nil {
+		return err
+	}
+	if err := r.setViewData(ctx, s.GroupId, s.TableId, viewData, nil); err != nil {
+		var notFound *metabase.NotFoundError
+		if !errors.As(err, &notFound) {
+			return err
+		}
+	}
+
+	path := fmt.Sprintf("/api/mt/gtap/%d", s.Id)
+	resp, err := r.client.Delete(ctx, path)
+	if err != nil {
+		// Idempotent delete: if the sandbox is already gone, treat as success.
+		var notFound *metabase.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// TableViewData returns the group's view-data level on the table; "blocked"
+// (Metabase's default) when the graph has none.
+func (r *SandboxRepository) TableViewData(ctx context.Context, groupId int, tableId int) (string, error) {
+	if err := r.client.Require(sandboxRequirement); err != nil {
+		return "", err
+	}
+	table, err := r.tables.Get(ctx, strconv.Itoa(tableId))
+	if err != nil {
+		return "", err
+	}
+	g, err := r.graph.get(ctx)
+	if err != nil {
+		return "", err
+	}
+	viewData := g.Groups[strconv.Itoa(groupId)][strconv.Itoa(table.DbId)]["view-data"]
+	return tableViewData(viewData, schemaName(table.Schema), strconv.Itoa(tableId)), nil
+}
+
+// setViewData writes the group's view-data level on one table, with extra
+// keys in the graph PUT. The edge is a granular {"<schema>": {"<table id>":
+// level}} view-data naming every table of the database at its current level,
+// so that only this table's level changes. A database-level "impersonated"
+// has no per-table form: the other tables get "blocked".
+func (r *SandboxRepository) setViewData(ctx context.Context, groupId int, tableId int, level string, extra map[string]any) error {
+	table, err := r.tables.Get(ctx, strconv.Itoa(tableId))
+	if err != nil {
+		return err
+	}
+	databaseId := strconv.Itoa(table.DbId)
+	tables, err := r.tables.List(ctx, databaseId)
+	if err != nil {
+		return err
+	}
+
+	group := strconv.Itoa(groupId)
+	return r.graph.putGraph(ctx, group, func(g *dataGraph) map[string]any {
+		current := g.Groups[group][databaseId]["view-data"]
+		_, granular := current.(map[string]any)
+		levels := map[string]map[string]any{}
+		for _, t := range tables {
+			schema, id := schemaName(t.Schema), strconv.Itoa(t.Id)
+			if levels[schema] == nil {
+				levels[schema] = map[string]any{}
+			}
+			level := tableViewData(current, schema, id)
+			if !granular && IsPolicyViewData(level) {
+				level = "blocked"
+			}
+			levels[schema][id] = level
+		}
+		schema := schemaName(table.Schema)
+		if levels[schema] == nil {
+			levels[schema] = map[string]any{}
+		}
+		levels[schema][strconv.Itoa(tableId)] = level
+		return map[string]any{databaseId: map[string]any{"view-data": levels}}
+	}, extra)
+}
+
+// tableViewData returns a table's level in a database's view-data: a single
+// level, or granular per schema and table. Defaults to "blocked".
+func tableViewData(viewData any, schema string, tableId string) string {
+	switch v := viewData.(type) {
+	case string:
+		return v
+	case map[string]any:
+		switch s := v[schema].(type) {
+		case string:
+			return s
+		case map[string]any:
+			if level, ok := s[tableId].(string); ok {
+				return level
+			}
+		}
+	}
+	return "blocked"
+}
+
+func schemaName(schema *string) string {
+	if schema == nil {
+		return ""
+	}
+	return *schema
+}
+
+func sandboxFromAPI(s sandbox) *dtos.SandboxDTO {
+	res := &dtos.SandboxDTO{
+		Id:                  s.Id,
+		GroupId:             s.GroupId,
+		TableId:             s.TableId,
+		CardId:              s.CardId,
+		AttributeRemappings: make(map[string]dtos.SandboxTargetDTO, len(s.AttributeRemappings)),
+	}
+	for attribute, target := range s.AttributeRemappings {
+		res.AttributeRemappings[attribute] = sandboxTargetFromAPI(target)
+	}
+	return res
+}
+
+// sandboxTargetToAPI returns the parameter target of a remapping:
+// ["dimension", ["field", id, null]] for a field, ["variable",
+// ["template-tag", name]] for a template tag.
+func sandboxTargetToAPI(target dtos.SandboxTargetDTO) []any {
+	if target.TemplateTag != nil {
+		return []any{"variable", []any{"template-tag", *target.TemplateTag}}
+	}
+	return []any{"dimension", []any{"field", target.FieldId, nil}}
+}
+
+// sandboxTargetFromAPI parses a parameter target, e.g. ["dimension", ["field",
+// 42, {"base-type": "type/Integer"}]] or ["dimension", ["template-tag",
+// "account"]] (field filter tags are "dimension" targets too).
+func sandboxTargetFromAPI(v any) dtos.SandboxTargetDTO {
+	var target dtos.SandboxTargetDTO
+	outer, ok := v.([]any)
+	if !ok || len(outer) < 2 {
+		return target
+	}
+	ref, ok := outer[1].([]any)
+	if !ok || len(ref) < 2 {
+		return target
+	}
+	switch ref[0] {
+	case "field":
+		if id, ok := ref[1].(float64); ok {
+			fieldId := int(id)
+			target.FieldId = &fieldId
+		}
+	case "template-tag":
+		if name, ok := ref[1].(string); ok {
+			target.TemplateTag = &name
+		}
+	}
+	return target
+}
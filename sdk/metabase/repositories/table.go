@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
+	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
+)
+
+// Tables are created by Metabase's schema sync, never through the API.
+type TableRepository struct {
+	client *metabase.MetabaseAPIClient
+}
+
+func NewTableRepository(client *metabase.MetabaseAPIClient) *TableRepository {
+	return &TableRepository{client: client}
+}
+
+func (r *TableRepository) Get(ctx context.Context, id string) (*dtos.TableDTO, error) {
+	path := fmt.Sprintf("/api/table/%s", id)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.TableDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode get response: %w", err)
+	}
+	return &res, nil
+}
+
+// UpdateMetadata writes the table's curated metadata and returns the table.
+func (r *TableRepository) UpdateMetadata(ctx context.Context, id string, metadata dtos.TableMetadataDTO) (*dtos.TableDTO, error) {
+	body := map[string]any{}
+	if metadata.DisplayName != nil {
+		body["display_name"] = *metadata.DisplayName
+	}
+	if metadata.Description != nil {
+		body["description"] = nilIfEmpty(*metadata.Description)
+	}
+	if metadata.VisibilityType != nil {
+		body["visibility_type"] = nilIfEmpty(*metadata.VisibilityType)
+	}
+	if metadata.EntityType != nil {
+		body["entity_type"] = *metadata.EntityType
+	}
+
+	path := fmt.Sprintf("/api/table/%s", id)
+	resp, err := r.client.Put(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.TableDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode update response: %w", err)
+	}
+	return &res, nil
+}
+
+// List returns every table of a database, as synced by Metabase.
+func (r *TableRepository) List(ctx context.Context, databaseId string) ([]dtos.TableDTO, error) {
+	path := fmt.Sprintf("/api/database/%s?include=tables", databaseId)
+	resp, err := r.client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var database struct {
+		Tables []dtos.TableDTO `json:"tables"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&database); err != nil {
+		return nil, fmt.Errorf("failed to decode database tables: %w", err)
+	}
+	return database.Tables, nil
+}
+
+// FindByName returns the table with the given name in a database, or nil if
+// none exists. schema narrows the search; without it, a name present in
+// several schemas is an error.
+func (r *TableRepository) FindByName(ctx context.Context, databaseId string, schema *string, name string) (*dtos.TableDTO, error) {
+	tables, err := r.List(ctx, databaseId)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []dtos.TableDTO
+	for _, t := range tables {
+		if !t.Active {
+			continue
+		}
+		if t.Name != name {
+			continue
+		}
+		if schema != nil && (t.Schema == nil || *t.Schema != *schema) {
+			continue
+		}
+		found = append(found, t)
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("table %q exists in %d schemas of database %s; set schema to pick one", name, len(found), databaseId)
+	}
+}
+
+// nilIfEmpty maps "" to JSON null, which is how Metabase clears a value.
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase"
@@ -23,8 +24,14 @@ func NewUserRepository(client *metabase.MetabaseAPIClient) *UserRepository {
 	return &UserRepository{client: client}
 }
 
-func (r *UserRepository) Create(ctx context.Context, email string, firstName string, lastName string) (*dtos.UserDTO, error) {
-	body := map[string]string{"email": email, "first_name": firstName, "last_name": lastName}
+// Create creates the user; options other than the login attributes are only
+// accepted on update, so they are written by a second request. When that
+// request fails, the created user is returned along with the error.
+func (r *UserRepository) Create(ctx context.Context, email string, firstName string, lastName string, options dtos.UserOptionsDTO) (*dtos.UserDTO, error) {
+	body := map[string]any{"email": email, "first_name": firstName, "last_name": lastName}
+	if options.LoginAttributes != nil {
+		body["login_attributes"] = options.LoginAttributes
+	}
 
 	resp, err := r.client.Post(ctx, "/api/user", body)
 	if err != nil {
@@ -42,6 +49,45 @@ func (r *UserRepository) Create(ctx context.Context, email string, firstName str
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, fmt.Errorf("failed to decode create response: %w", err)
 	}
+
+	options.LoginAttributes = nil
+	if body := userOptionsBody(options); len(body) > 0 {
+		updated, err := r.put(ctx, strconv.Itoa(res.Id), body)
+		if err != nil {
+			return &res, fmt.Errorf("user %d was created, but setting its options failed: %w", res.Id, err)
+		}
+		return updated, nil
+	}
+	return &res, nil
+}
+
+// userOptionsBody returns the PUT body fields of the managed options.
+func userOptionsBody(options dtos.UserOptionsDTO) map[string]any {
+	body := map[string]any{}
+	if options.IsSuperuser != nil {
+		body["is_superuser"] = *options.IsSuperuser
+	}
+	if options.LoginAttributes != nil {
+		body["login_attributes"] = options.LoginAttributes
+	}
+	if options.Locale != nil {
+		body["locale"] = nilIfEmpty(*options.Locale)
+	}
+	return body
+}
+
+func (r *UserRepository) put(ctx context.Context, id string, body map[string]any) (*dtos.UserDTO, error) {
+	path := fmt.Sprintf("/api/user/%s", id)
+	resp, err := r.client.Put(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res dtos.UserDTO
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode update response: %w", err)
+	}
 	return &res, nil
 }
 
@@ -60,27 +106,24 @@ func (r *UserRepository) emailInUseError(ctx context.Context, email string) erro
 // FindByEmail returns the user with this email (any status), or nil. Case-insensitive.
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*dtos.UserDTO, error) {
 	path := fmt.Sprintf("/api/user?status=all&query=%s", url.QueryEscape(email))
-	resp, err := r.client.Get(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var listResponse struct {
-		Data []dtos.UserDTO `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&listResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode user search response: %w", err)
-	}
-
-	for i := range listResponse.Data {
-		if strings.EqualFold(listResponse.Data[i].Email, email) {
-			return &listResponse.Data[i], nil
+	for user, err := range metabase.Paginate[dtos.UserDTO](ctx, r.client, path, metabase.DefaultPageSize) {
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(user.Email, email) {
+			return &user, nil
 		}
 	}
 	return nil, nil
 }
 
+// List returns every user with the given status ("active", "deactivated" or
+// "all").
+func (r *UserRepository) List(ctx context.Context, status string) ([]dtos.UserDTO, error) {
+	path := fmt.Sprintf("/api/user?status=%s", url.QueryEscape(status))
+	return metabase.ListAll[dtos.UserDTO](ctx, r.client, path)
+}
+
 func (r *UserRepository) Get(ctx context.Context, id string) (*dtos.UserDTO, error) {
 	path := fmt.Sprintf("/api/user/%s", id)
 	resp, err := r.client.Get(ctx, path)
@@ -126,7 +169,7 @@ func (r *UserRepository) reactivate(ctx context.Context, id string) (bool, error
 	return true, nil
 }
 
-func (r *UserRepository) Update(ctx context.Context, id string, email *string, firstName *string, lastName *string, isActive *bool) (bool, error) {
+func (r *UserRepository) Update(ctx context.Context, id string, email *string, firstName *string, lastName *string, isActive *bool, options dtos.UserOptionsDTO) (bool, error) {
 	// PUT 404s on a deactivated user, so reactivate before editing and deactivate after.
 	if isActive != nil && *isActive {
 		if _, err := r.reactivate(ctx, id); err != nil {
@@ -134,8 +177,8 @@ func (r *UserRepository) Update(ctx context.Context, id string, email *string, f
 		}
 	}
 
-	if email != nil || firstName != nil || lastName != nil {
-		body := map[string]any{}
+	body := userOptionsBody(options)
+	if email != nil || firstName != nil || lastName != nil || len(body) > 0 {
 		if email != nil {
 			body["email"] = *email
 		}
@@ -146,12 +189,9 @@ func (r *UserRepository) Update(ctx context.Context, id string, email *string, f
 			body["last_name"] = *lastName
 		}
 
-		path := fmt.Sprintf("/api/user/%s", id)
-		resp, err := r.client.Put(ctx, path, body)
-		if err != nil {
+		if _, err := r.put(ctx, id, body); err != nil {
 			return false, err
 		}
-		resp.Body.Close()
 	}
 
 	if isActive != nil && !*isActive {
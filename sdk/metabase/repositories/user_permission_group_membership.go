@@ -14,6 +14,12 @@ import (
 	"github.com/csp33/terraform-provider-metabase/sdk/metabase/models/dtos"
 )
 
+// Group managers are an Enterprise "advanced permissions" feature.
+var groupManagerRequirement = metabase.Requirement{
+	Feature:      "metabase_permission_group_members group_managers",
+	TokenFeature: "advanced_permissions",
+}
+
 type UserPermissionGroupMembershipRepository struct {
 	client *metabase.MetabaseAPIClient
 }
@@ -22,6 +28,12 @@ func NewUserPermissionGroupMembershipRepository(client *metabase.MetabaseAPIClie
 	return &UserPermissionGroupMembershipRepository{client: client}
 }
 
+// SupportsGroupManagers returns an *metabase.UnsupportedError when the
+// instance has no group managers.
+func (r *UserPermissionGroupMembershipRepository) SupportsGroupManagers() error {
+	return r.client.Require(groupManagerRequirement)
+}
+
 func (r *UserPermissionGroupMembershipRepository) Create(ctx context.Context, userId string, groupId string) (*dtos.UserPermissionGroupMembershipDTO, error) {
 	// A duplicate (user, group) membership 500s; pre-check and return an import hint.
 	if existing, err := r.findByUserAndGroup(ctx, userId, groupId); err != nil {
@@ -56,50 +68,97 @@ func (r *UserPermissionGroupMembershipRepository) Create(ctx context.Context, us
 }
 
 func (r *UserPermissionGroupMembershipRepository) Get(ctx context.Context, id string) (*dtos.UserPermissionGroupMembershipDTO, error) {
-	path := "/api/permissions/membership"
-	resp, err := r.client.Get(ctx, path)
+	all, err := r.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, memberships := range all {
+		for i := range memberships {
+			if strconv.Itoa(memberships[i].MembershipId) == id {
+				return &memberships[i], nil
+			}
+		}
+	}
+	return nil, metabase.NewNotFoundError(fmt.Sprintf("Membership with ID %s not found", id))
+}
+
+// list returns every membership of the instance, keyed by user id.
+func (r *UserPermissionGroupMembershipRepository) list(ctx context.Context) (map[string][]dtos.UserPermissionGroupMembershipDTO, error) {
+	resp, err := r.client.Get(ctx, "/api/permissions/membership")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var res map[string][]dtos.UserPermissionGroupMembershipDTO
-
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, fmt.Errorf("failed to decode get response: %w", err)
+		return nil, fmt.Errorf("failed to decode membership list: %w", err)
 	}
+	return res, nil
+}
 
-	for _, memberships := range res {
+// ListGroup returns the memberships of one group, from a single listing.
+func (r *UserPermissionGroupMembershipRepository) ListGroup(ctx context.Context, groupId string) ([]dtos.UserPermissionGroupMembershipDTO, error) {
+	all, err := r.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []dtos.UserPermissionGroupMembershipDTO
+	for _, memberships := range all {
 		for _, m := range memberships {
-			if strconv.Itoa(m.MembershipId) == id {
-				return &m, nil
+			if strconv.Itoa(m.GroupId) == groupId {
+				res = append(res, m)
 			}
 		}
 	}
-	return nil, metabase.NewNotFoundError(fmt.Sprintf("Membership with ID %s not found", id))
+	return res, nil
 }
 
 // findByUserAndGroup returns the membership linking this user and group, or nil.
 func (r *UserPermissionGroupMembershipRepository) findByUserAndGroup(ctx context.Context, userId string, groupId string) (*dtos.UserPermissionGroupMembershipDTO, error) {
-	resp, err := r.client.Get(ctx, "/api/permissions/membership")
+	all, err := r.list(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// The listing is keyed by user id, so only this user's memberships are scanned.
+	for _, m := range all[userId] {
+		if strconv.Itoa(m.GroupId) == groupId {
+			return &m, nil
+		}
+	}
+	return nil, nil
+}
+
+// Add adds the user to the group. isGroupManager is only sent when set, as
+// group managers are an Enterprise feature.
+func (r *UserPermissionGroupMembershipRepository) Add(ctx context.Context, userId string, groupId string, isGroupManager *bool) error {
+	body := map[string]any{"group_id": groupId, "user_id": userId}
+	if isGroupManager != nil {
+		if err := r.client.Require(groupManagerRequirement); err != nil {
+			return err
+		}
+		body["is_group_manager"] = *isGroupManager
+	}
+	resp, err := r.client.Post(ctx, "/api/permissions/membership", body)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
+	return nil
+}
 
-	var res map[string][]dtos.UserPermissionGroupMembershipDTO
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return nil, fmt.Errorf("failed to decode membership list: %w", err)
+// SetGroupManager promotes the member to group manager, or demotes them.
+func (r *UserPermissionGroupMembershipRepository) SetGroupManager(ctx context.Context, membershipId int, isGroupManager bool) error {
+	if err := r.client.Require(groupManagerRequirement); err != nil {
+		return err
 	}
-	for _, memberships := range res {
-		for i := range memberships {
-			m := memberships[i]
-			if strconv.Itoa(m.UserId) == userId && strconv.Itoa(m.GroupId) == groupId {
-				return &m, nil
-			}
-		}
+	path := fmt.Sprintf("/api/permissions/membership/%d", membershipId)
+	resp, err := r.client.Put(ctx, path, map[string]any{"is_group_manager": isGroupManager})
+	if err != nil {
+		return err
 	}
-	return nil, nil
+	defer resp.Body.Close()
+	return nil
 }
 
 func (r *UserPermissionGroupMembershipRepository) Delete(ctx context.Context, id string) error {
@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how request retries transient failures: 5xx, 429 and
+// network errors. The zero value disables retries (one attempt per request).
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// MinBackoff is the wait before the first retry; it doubles on each retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the computed wait (Retry-After is honoured up to it).
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used by NewMetabaseAPIClient.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 4,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// WithRetryPolicy overrides the client's retry policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(m *MetabaseAPIClient) {
+		m.RetryPolicy = policy
+	}
+}
+
+type withoutRetriesKey struct{}
+
+// WithoutRetries returns a context whose requests are attempted once, for
+// callers that retry at their own level (e.g. revision-checked graph writes),
+// so that a failure is not retried at both.
+func WithoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutRetriesKey{}, true)
+}
+
+// maxRetries returns the policy's MaxRetries, or 0 under WithoutRetries.
+func (p RetryPolicy) maxRetries(ctx context.Context) int {
+	if disabled, _ := ctx.Value(withoutRetriesKey{}).(bool); disabled {
+		return 0
+	}
+	return p.MaxRetries
+}
+
+// WaitBeforeRetry waits before a caller-level retry of a request that failed
+// with err (see WithoutRetries), as the client waits between its own
+// attempts, or until ctx is done. retry is 1-based.
+func (m *MetabaseAPIClient) WaitBeforeRetry(ctx context.Context, retry int, err error) error {
+	return sleep(ctx, m.RetryPolicy.backoff(retry, errorHeader(err)))
+}
+
+// backoff returns the wait before the given retry (1-based): the server's
+// Retry-After when present, else exponential backoff with equal jitter.
+func (p RetryPolicy) backoff(retry int, header http.Header) time.Duration {
+	if wait, ok := retryAfter(header); ok {
+		if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+			return p.MaxBackoff
+		}
+		return wait
+	}
+
+	wait := p.MinBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := wait / 2
+	return half + rand.N(wait-half+1)
+}
+
+// retryAfter parses a Retry-After header, either delay-seconds or an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// shouldRetry reports whether an attempt is worth repeating. Only idempotent
+// methods are retried on 5xx and network errors (a POST may have been applied
+// before the failure); a 429 means the request was refused, so any method is.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return isIdempotent(method) && isTransientNetworkError(err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return isIdempotent(method) && resp.StatusCode >= 500
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransientNetworkError matches connection resets/refusals, timeouts and
+// truncated responses; context cancellation is never retried.
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
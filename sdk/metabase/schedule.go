@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Schedule is a sync or scan schedule in the expanded form Metabase's API
+// accepts (and its admin UI edits). It stores them as Quartz cron strings.
+type Schedule struct {
+	// Type is "hourly", "daily", "weekly" or "monthly".
+	Type string
+	// Minute of the hour (0-59).
+	Minute int
+	// Hour of the day (0-23); ignored for hourly schedules.
+	Hour int
+	// Day is "sun" … "sat" for weekly schedules and monthly ones on a weekday.
+	Day string
+	// Frame is "first", "mid" or "last" for monthly schedules ("mid" only
+	// without a Day).
+	Frame string
+}
+
+var cronDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseCronSchedule parses a Quartz cron expression ("sec min hour
+// day-of-month month day-of-week [year]") into a Schedule. Only the shapes
+// Metabase can express are accepted:
+//
+//	0 M * * * ? *      hourly at minute M
+//	0 M H * * ? *      daily at H:M
+//	0 M H ? * D *      weekly on day D (1-7 from Sunday, or SUN-SAT)
+//	0 M H 1|15|L * ? * monthly on the first, 15th or last day
+//	0 M H ? * D#1|DL * monthly on the first or last weekday D
+func ParseCronSchedule(cron string) (*Schedule, error) {
+	fields := strings.Fields(cron)
+	if len(fields) == 7 {
+		if fields[6] != "*" {
+			return nil, fmt.Errorf("invalid schedule %q: the year must be *", cron)
+		}
+		fields = fields[:6]
+	}
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 6 or 7 cron fields", cron)
+	}
+	seconds, minutes, hours, dayOfMonth, month, dayOfWeek := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+	if seconds != "0" || month != "*" {
+		return nil, fmt.Errorf("invalid schedule %q: seconds must be 0 and month *", cron)
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.Minute, err = cronNumber(minutes, 59); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: minute: %w", cron, err)
+	}
+	if hours == "*" {
+		if !isCronAny(dayOfMonth) || !isCronAny(dayOfWeek) {
+			return nil, fmt.Errorf("invalid schedule %q: an hourly schedule can't restrict the day", cron)
+		}
+		s.Type = "hourly"
+		return s, nil
+	}
+	if s.Hour, err = cronNumber(hours, 23); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: hour: %w", cron, err)
+	}
+
+	switch {
+	case isCronAny(dayOfMonth) && isCronAny(dayOfWeek):
+		s.Type = "daily"
+	case isCronAny(dayOfWeek):
+		s.Type = "monthly"
+		switch dayOfMonth {
+		case "1":
+			s.Frame = "first"
+		case "15":
+			s.Frame = "mid"
+		case "L":
+			s.Frame = "last"
+		default:
+			return nil, fmt.Errorf("invalid schedule %q: the day of month must be 1, 15 or L", cron)
+		}
+	case isCronAny(dayOfMonth):
+		day, frame := dayOfWeek, ""
+		if d, ok := strings.CutSuffix(day, "#1"); ok {
+			day, frame = d, "first"
+		} else if d, ok := strings.CutSuffix(day, "L"); ok && d != "" {
+			day, frame = d, "last"
+		}
+		if s.Day, err = cronDay(day); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: day of week: %w", cron, err)
+		}
+		s.Type, s.Frame = "weekly", frame
+		if frame != "" {
+			s.Type = "monthly"
+		}
+	default:
+		return nil, fmt.Errorf("invalid schedule %q: set the day of month or the day of week, not both", cron)
+	}
+	return s, nil
+}
+
+// Map returns the schedule as the API's schedule map.
+func (s Schedule) Map() map[string]any {
+	m := map[string]any{
+		"schedule_type":   s.Type,
+		"schedule_minute": s.Minute,
+		"schedule_hour":   nil,
+		"schedule_day":    nil,
+		"schedule_frame":  nil,
+	}
+	if s.Type != "hourly" {
+		m["schedule_hour"] = s.Hour
+	}
+	if s.Day != "" {
+		m["schedule_day"] = s.Day
+	}
+	if s.Frame != "" {
+		m["schedule_frame"] = s.Frame
+	}
+	return m
+}
+
+// SameCronSchedule reports whether two cron expressions describe the same
+// Metabase schedule, e.g. "0 0 2 * * ?" and "0 0 2 * * ? *".
+func SameCronSchedule(a, b string) bool {
+	sa, err := ParseCronSchedule(a)
+	if err != nil {
+		return false
+	}
+	sb, err := ParseCronSchedule(b)
+	if err != nil {
+		return false
+	}
+	return *sa == *sb
+}
+
+func isCronAny(field string) bool {
+	return field == "*" || field == "?"
+}
+
+func cronNumber(field string, max int) (int, error) {
+	n, err := strconv.Atoi(field)
+	if err != nil || n < 0 || n > max {
+		return 0, fmt.Errorf("expected a number from 0 to %d, got %q", max, field)
+	}
+	return n, nil
+}
+
+func cronDay(field string) (string, error) {
+	if n, err := strconv.Atoi(field); err == nil && n >= 1 && n <= len(cronDays) {
+		return cronDays[n-1], nil
+	}
+	for _, d := range cronDays {
+		if strings.EqualFold(field, d) {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("expected 1-7 or SUN-SAT, got %q", field)
+}
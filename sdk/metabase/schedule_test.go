@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	tests := []struct {
+		cron    string
+		want    Schedule
+		wantErr bool
+	}{
+		{"0 30 * * * ? *", Schedule{Type: "hourly", Minute: 30}, false},
+		{"0 0 2 * * ? *", Schedule{Type: "daily", Hour: 2}, false},
+		{"0 15 23 * * ?", Schedule{Type: "daily", Minute: 15, Hour: 23}, false},
+		{"0 0 4 ? * 2 *", Schedule{Type: "weekly", Hour: 4, Day: "mon"}, false},
+		{"0 0 4 ? * SAT", Schedule{Type: "weekly", Hour: 4, Day: "sat"}, false},
+		{"0 0 1 1 * ? *", Schedule{Type: "monthly", Hour: 1, Frame: "first"}, false},
+		{"0 0 1 15 * ? *", Schedule{Type: "monthly", Hour: 1, Frame: "mid"}, false},
+		{"0 0 1 L * ? *", Schedule{Type: "monthly", Hour: 1, Frame: "last"}, false},
+		{"0 0 1 ? * 6#1 *", Schedule{Type: "monthly", Hour: 1, Day: "fri", Frame: "first"}, false},
+		{"0 0 1 ? * 1L *", Schedule{Type: "monthly", Hour: 1, Day: "sun", Frame: "last"}, false},
+		{"0 0 2 * *", Schedule{}, true},
+		{"30 0 2 * * ? *", Schedule{}, true},
+		{"0 */5 * * * ? *", Schedule{}, true},
+		{"0 0 24 * * ? *", Schedule{}, true},
+		{"0 0 2 10 * ? *", Schedule{}, true},
+		{"0 0 2 1 * 2 *", Schedule{}, true},
+		{"0 0 * ? * 2 *", Schedule{}, true},
+		{"0 0 2 ? * 8 *", Schedule{}, true},
+		{"0 0 2 * 1 ? *", Schedule{}, true},
+		{"0 0 2 * * ? 2030", Schedule{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cron, func(t *testing.T) {
+			got, err := ParseCronSchedule(tt.cron)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestScheduleMap(t *testing.T) {
+	hourly := Schedule{Type: "hourly", Minute: 30, Hour: 5}.Map()
+	want := map[string]any{"schedule_type": "hourly", "schedule_minute": 30, "schedule_hour": nil, "schedule_day": nil, "schedule_frame": nil}
+	if !reflect.DeepEqual(hourly, want) {
+		t.Errorf("expected %v, got %v", want, hourly)
+	}
+
+	monthly := Schedule{Type: "monthly", Hour: 1, Day: "fri", Frame: "first"}.Map()
+	want = map[string]any{"schedule_type": "monthly", "schedule_minute": 0, "schedule_hour": 1, "schedule_day": "fri", "schedule_frame": "first"}
+	if !reflect.DeepEqual(monthly, want) {
+		t.Errorf("expected %v, got %v", want, monthly)
+	}
+}
+
+func TestSameCronSchedule(t *testing.T) {
+	if !SameCronSchedule("0 0 2 * * ?", "0 0 2 * * ? *") {
+		t.Error("expected equivalent daily schedules to match")
+	}
+	if !SameCronSchedule("0 0 4 ? * MON *", "0 0 4 ? * 2 *") {
+		t.Error("expected a day name to match its number")
+	}
+	if SameCronSchedule("0 0 2 * * ? *", "0 0 3 * * ? *") {
+		t.Error("expected different hours not to match")
+	}
+	if SameCronSchedule("bogus", "bogus") {
+		t.Error("expected unparseable schedules not to match")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// sessionHeader carries the token returned by POST /api/session.
+const sessionHeader = "X-Metabase-Session"
+
+// WithSessionCredentials authenticates with username/password instead of an
+// API key: the client logs in on first use via POST /api/session, caches the
+// session token, and logs in again when Metabase answers 401.
+func WithSessionCredentials(username, password string) ClientOption {
+	return func(m *MetabaseAPIClient) {
+		m.Username = username
+		m.Password = password
+	}
+}
+
+func (m *MetabaseAPIClient) usesSession() bool {
+	return m.Username != ""
+}
+
+// session returns the cached session token, logging in if there is none.
+func (m *MetabaseAPIClient) session(ctx context.Context) (string, error) {
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+
+	if m.sessionToken != "" {
+		return m.sessionToken, nil
+	}
+	return m.login(ctx)
+}
+
+// renewSession logs in again after staleToken was rejected. Concurrent
+// requests rejected with the same token share a single new login.
+func (m *MetabaseAPIClient) renewSession(ctx context.Context, staleToken string) (string, error) {
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+
+	if m.sessionToken != "" && m.sessionToken != staleToken {
+		return m.sessionToken, nil
+	}
+	return m.login(ctx)
+}
+
+// login must be called with sessionMu held.
+func (m *MetabaseAPIClient) login(ctx context.Context) (string, error) {
+	m.sessionToken = ""
+
+	body, err := json.Marshal(map[string]string{"username": m.Username, "password": m.Password})
+	if err != nil {
+		return "", err
+	}
+	resp, err := m.send(ctx, http.MethodPost, "/api/session", body, nil)
+	if err != nil {
+		var unauthorized *UnauthorizedError
+		var badRequest *BadRequestError
+		if errors.As(err, &unauthorized) || errors.As(err, &badRequest) {
+			return "", fmt.Errorf("unable to log in to Metabase as %q, check the username and password: %w", m.Username, err)
+		}
+		return "", fmt.Errorf("unable to log in to Metabase: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Id string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("failed to decode session response: %w", err)
+	}
+	if res.Id == "" {
+		return "", errors.New("metabase returned an empty session token")
+	}
+	m.sessionToken = res.Id
+	return m.sessionToken, nil
+}
+
+// Logout ends the cached session, if any. A no-op for API key clients.
+func (m *MetabaseAPIClient) Logout(ctx context.Context) error {
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+
+	if m.sessionToken == "" {
+		return nil
+	}
+	token := m.sessionToken
+	m.sessionToken = ""
+
+	resp, err := m.send(ctx, http.MethodDelete, "/api/session", nil, map[string]string{sessionHeader: token})
+	if err != nil {
+		// Already expired or revoked: nothing left to end.
+		var unauthorized *UnauthorizedError
+		if errors.As(err, &unauthorized) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeSessionServer is an httptest stand-in for Metabase's session endpoints:
+// it issues sequential tokens and only accepts the ones still live.
+type fakeSessionServer struct {
+	mu      sync.Mutex
+	issued  int
+	live    map[string]bool
+	logins  int
+	logouts int
+}
+
+func newFakeSessionServer(t *testing.T) (*fakeSessionServer, *httptest.Server) {
+	t.Helper()
+	f := &fakeSessionServer{live: map[string]bool{}}
+	srv := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeSessionServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/session":
+		var creds map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&creds)
+		if creds["username"] != "admin@example.com" || creds["password"] != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors":{"password":"did not match stored password"}}`))
+			return
+		}
+		f.logins++
+		f.issued++
+		token := fmt.Sprintf("token-%d", f.issued)
+		f.live[token] = true
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": token})
+	case r.Method == http.MethodDelete && r.URL.Path == "/api/session":
+		f.logouts++
+		delete(f.live, r.Header.Get(sessionHeader))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		if !f.live[r.Header.Get(sessionHeader)] {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("Unauthenticated"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}
+}
+
+// expireAll revokes every live session, like a server-side timeout.
+func (f *fakeSessionServer) expireAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.live = map[string]bool{}
+}
+
+func TestSession_LoginIsCached(t *testing.T) {
+	fake, srv := newFakeSessionServer(t)
+	client := NewMetabaseAPIClient(srv.URL, "", WithSessionCredentials("admin@example.com", "secret"))
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(context.Background(), "/api/user/current")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if fake.logins != 1 {
+		t.Errorf("expected a single login, got %d", fake.logins)
+	}
+}
+
+func TestSession_ReloginOnUnauthorized(t *testing.T) {
+	fake, srv := newFakeSessionServer(t)
+	client := NewMetabaseAPIClient(srv.URL, "", WithSessionCredentials("admin@example.com", "secret"))
+
+	resp, err := client.Get(context.Background(), "/api/user/current")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	fake.expireAll()
+
+	resp, err = client.Put(context.Background(), "/api/user/1", map[string]string{"first_name": "x"})
+	if err != nil {
+		t.Fatalf("expected the request to be replayed after a new login, got %v", err)
+	}
+	resp.Body.Close()
+
+	if fake.logins != 2 {
+		t.Errorf("expected 2 logins, got %d", fake.logins)
+	}
+}
+
+func TestSession_BadCredentials(t *testing.T) {
+	fake, srv := newFakeSessionServer(t)
+	client := NewMetabaseAPIClient(srv.URL, "", WithSessionCredentials("admin@example.com", "wrong"))
+
+	_, err := client.Get(context.Background(), "/api/user/current")
+	if err == nil {
+		t.Fatalf("expected a login error, got nil")
+	}
+	var unauthorized *UnauthorizedError
+	if !errors.As(err, &unauthorized) {
+		t.Errorf("expected the login error to wrap UnauthorizedError, got %T: %v", err, err)
+	}
+	if fake.logins != 0 {
+		t.Errorf("expected no successful login, got %d", fake.logins)
+	}
+}
+
+func TestSession_Logout(t *testing.T) {
+	fake, srv := newFakeSessionServer(t)
+	client := NewMetabaseAPIClient(srv.URL, "", WithSessionCredentials("admin@example.com", "secret"))
+
+	// Logout before any request is a no-op.
+	if err := client.Logout(context.Background()); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if fake.logouts != 0 {
+		t.Errorf("expected no logout call without a session, got %d", fake.logouts)
+	}
+
+	resp, err := client.Get(context.Background(), "/api/user/current")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if err := client.Logout(context.Background()); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if fake.logouts != 1 || len(fake.live) != 0 {
+		t.Errorf("expected the session to be ended, got %d logouts and %d live sessions", fake.logouts, len(fake.live))
+	}
+}
+
+func TestSession_APIKeyClientSendsNoSession(t *testing.T) {
+	fake, srv := newFakeSessionServer(t)
+	client := NewMetabaseAPIClient(srv.URL, "test-key")
+
+	// The fake only accepts session tokens, so an API key request gets 401 and
+	// must not trigger a login.
+	_, err := client.Get(context.Background(), "/api/user/current")
+	var unauthorized *UnauthorizedError
+	if !errors.As(err, &unauthorized) {
+		t.Fatalf("expected UnauthorizedError, got %v", err)
+	}
+	if fake.logins != 0 {
+		t.Errorf("expected no login for an API key client, got %d", fake.logins)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TransportConfig describes how the client reaches Metabase. The zero value
+// behaves like http.DefaultTransport with no timeout.
+type TransportConfig struct {
+	// Timeout bounds each attempt, including reading the response body.
+	Timeout time.Duration
+	// CACertPEM holds extra PEM root certificates trusted besides the system pool.
+	CACertPEM []byte
+	// ClientCertPEM and ClientKeyPEM enable mutual TLS; both or neither.
+	ClientCertPEM []byte
+	ClientKeyPEM  []byte
+	// InsecureSkipVerify disables server certificate verification.
+	InsecureSkipVerify bool
+	// ProxyURL overrides the HTTP_PROXY/HTTPS_PROXY environment variables.
+	ProxyURL string
+}
+
+// NewHTTPClient builds the *http.Client described by cfg, for use with WithHTTPClient.
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("http.DefaultTransport is not an *http.Transport")
+	}
+	transport = transport.Clone()
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // opt-in, for staging instances
+	}
+	if len(cfg.CACertPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(cfg.CACertPEM) {
+			return nil, errors.New("no valid PEM certificate found in the CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(cfg.ClientCertPEM) > 0 || len(cfg.ClientKeyPEM) > 0 {
+		cert, err := tls.X509KeyPair(cfg.ClientCertPEM, cfg.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &http.Client{Transport: transport, Timeout: cfg.Timeout}, nil
+}
+
+// WithHTTPClient replaces the client's *http.Client (see NewHTTPClient).
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(m *MetabaseAPIClient) {
+		m.Client = client
+	}
+}
+
+// WithHeaders adds headers to every request, e.g. for an authenticating
+// proxy in front of Metabase. They cannot override Content-Type or the
+// Metabase credentials headers.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(m *MetabaseAPIClient) {
+		m.Headers = headers
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func serverCAPEM(srv *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+}
+
+func TestNewHTTPClient_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		cfg     TransportConfig
+		wantErr bool
+	}{
+		{"untrusted certificate is rejected", TransportConfig{}, true},
+		{"CA bundle is trusted", TransportConfig{CACertPEM: serverCAPEM(srv)}, false},
+		{"insecure_skip_verify", TransportConfig{InsecureSkipVerify: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := NewHTTPClient(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewHTTPClient failed: %v", err)
+			}
+			client := NewMetabaseAPIClient(srv.URL, "test-key", WithHTTPClient(httpClient), WithRetryPolicy(RetryPolicy{}))
+
+			resp, err := client.Get(context.Background(), "/api/user/current")
+			if err == nil {
+				resp.Body.Close()
+			}
+			if tt.wantErr != (err != nil) {
+				t.Errorf("expected error=%t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_InvalidConfig(t *testing.T) {
+	tests := map[string]TransportConfig{
+		"bad CA bundle":           {CACertPEM: []byte("not a certificate")},
+		"client cert without key": {ClientCertPEM: []byte("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n")},
+		"bad proxy URL":           {ProxyURL: "proxy.example.com:3128"},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewHTTPClient(cfg); err == nil {
+				t.Errorf("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	httpClient, err := NewHTTPClient(TransportConfig{ProxyURL: "http://proxy.example.com:3128"})
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", httpClient.Transport)
+	}
+	proxy, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "metabase.example.com"}})
+	if err != nil || proxy.String() != "http://proxy.example.com:3128" {
+		t.Errorf("expected the explicit proxy, got %v (err %v)", proxy, err)
+	}
+}
+
+func TestNewHTTPClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	httpClient, err := NewHTTPClient(TransportConfig{Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	client := NewMetabaseAPIClient(srv.URL, "test-key", WithHTTPClient(httpClient), WithRetryPolicy(RetryPolicy{}))
+
+	if _, err := client.Get(context.Background(), "/api/user/current"); err == nil {
+		t.Fatalf("expected a timeout error, got nil")
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	mockClient := &mockHTTPClient{
+		RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return status(http.StatusOK)()
+		},
+	}
+	client := NewMetabaseAPIClient("http://localhost:3000", "test-key",
+		WithHTTPClient(&http.Client{Transport: mockClient}),
+		WithHeaders(map[string]string{"X-Proxy-Auth": "token", "x-api-key": "override"}),
+	)
+
+	if _, err := client.Get(context.Background(), "/api/user/current"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	// Extra headers are sent, but never replace the credentials.
+	assertRequest(t, mockClient.LastRequest, http.MethodGet, "http://localhost:3000/api/user/current",
+		map[string]string{"X-Proxy-Auth": "token", "x-api-key": "test-key"}, nil)
+}
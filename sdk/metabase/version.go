@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a Metabase release. Metabase numbers releases 0.x (OSS) and 1.x
+// (Enterprise) with the same minor, so "v0.50.3" and "v1.50.3" are both
+// Version{Major: 50, Minor: 3}; the leading digit is the edition.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is release major or newer.
+func (v Version) AtLeast(major int) bool {
+	return v.Major >= major
+}
+
+// ServerInfo describes the Metabase instance, from /api/session/properties.
+type ServerInfo struct {
+	// Tag is the raw version tag, e.g. "v1.50.3".
+	Tag     string
+	Version Version
+	// Enterprise is true for Enterprise/Pro builds (tag "v1.x").
+	Enterprise bool
+	// Features are the enabled premium features of the license token, e.g.
+	// "sandboxes" or "advanced_permissions"; empty on OSS or without a token.
+	Features map[string]bool
+}
+
+// Edition returns "Enterprise" or "OSS".
+func (s *ServerInfo) Edition() string {
+	if s.Enterprise {
+		return "Enterprise"
+	}
+	return "OSS"
+}
+
+// ParseVersionTag parses a tag like "v0.50.3", "v1.49.10.2" or "v0.51.0-RC1".
+func ParseVersionTag(tag string) (Version, bool, error) {
+	trimmed := strings.TrimPrefix(tag, "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 {
+		return Version{}, false, fmt.Errorf("unrecognized Metabase version %q", tag)
+	}
+	nums := make([]int, 4)
+	for i := 0; i < len(parts) && i < len(nums); i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return Version{}, false, fmt.Errorf("unrecognized Metabase version %q", tag)
+		}
+		nums[i] = n
+	}
+	return Version{Major: nums[1], Minor: nums[2], Patch: nums[3]}, nums[0] == 1, nil
+}
+
+// FetchServerInfo reads the instance version and edition, caching the result
+// on the client for Require.
+func (m *MetabaseAPIClient) FetchServerInfo(ctx context.Context) (*ServerInfo, error) {
+	resp, err := m.Get(ctx, "/api/session/properties")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var properties struct {
+		Version struct {
+			Tag string `json:"tag"`
+		} `json:"version"`
+		TokenFeatures map[string]bool `json:"token-features"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&properties); err != nil {
+		return nil, fmt.Errorf("failed to decode session properties: %w", err)
+	}
+
+	version, enterprise, err := ParseVersionTag(properties.Version.Tag)
+	if err != nil {
+		return nil, err
+	}
+	features := map[string]bool{}
+	for name, enabled := range properties.TokenFeatures {
+		if enabled {
+			features[name] = true
+		}
+	}
+
+	info := &ServerInfo{Tag: properties.Version.Tag, Version: version, Enterprise: enterprise, Features: features}
+	m.ServerInfo = info
+	return info, nil
+}
+
+// Requirement describes what a feature needs from the instance.
+type Requirement struct {
+	// Feature names what is gated in messages, e.g. "metabase_sandbox".
+	Feature string
+	// MinVersion is the oldest supported release (e.g. 50); 0 means any.
+	MinVersion int
+	// Enterprise requires an Enterprise build.
+	Enterprise bool
+	// TokenFeature requires this premium feature to be enabled by the license.
+	TokenFeature string
+}
+
+// Require returns an *UnsupportedError when the instance cannot serve r. It
+// passes when the server info is unknown (FetchServerInfo not called or
+// failed), leaving Metabase to reject the request itself.
+func (m *MetabaseAPIClient) Require(r Requirement) error {
+	info := m.ServerInfo
+	if info == nil {
+		return nil
+	}
+
+	var needs []string
+	if r.MinVersion > 0 && !info.Version.AtLeast(r.MinVersion) {
+		needs = append(needs, fmt.Sprintf("Metabase >= %d", r.MinVersion))
+	}
+	if (r.Enterprise || r.TokenFeature != "") && !info.Enterprise {
+		needs = append(needs, "Enterprise")
+	} else if r.TokenFeature != "" && !info.Features[r.TokenFeature] {
+		needs = append(needs, fmt.Sprintf("a license with the %q feature", r.TokenFeature))
+	}
+	if len(needs) == 0 {
+		return nil
+	}
+	return &UnsupportedError{
+		Feature: r.Feature,
+		Needs:   strings.Join(needs, " / "),
+		Server:  fmt.Sprintf("%s %s", info.Tag, info.Edition()),
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseVersionTag(t *testing.T) {
+	tests := []struct {
+		tag            string
+		want           Version
+		wantEnterprise bool
+		wantErr        bool
+	}{
+		{"v0.50.3", Version{50, 3, 0}, false, false},
+		{"v1.50.3", Version{50, 3, 0}, true, false},
+		{"v1.49.10.2", Version{49, 10, 2}, true, false},
+		{"v0.51.0-RC1", Version{51, 0, 0}, false, false},
+		{"v0.62", Version{62, 0, 0}, false, false},
+		{"vUNKNOWN", Version{}, false, true},
+		{"", Version{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, enterprise, err := ParseVersionTag(tt.tag)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || enterprise != tt.wantEnterprise {
+				t.Errorf("expected %v (enterprise=%t), got %v (enterprise=%t)", tt.want, tt.wantEnterprise, got, enterprise)
+			}
+		})
+	}
+}
+
+func TestFetchServerInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/session/properties" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"version":{"tag":"v1.50.3","hash":"abc"},"token-features":{"sandboxes":true,"audit_app":false}}`))
+	}))
+	defer srv.Close()
+
+	client := NewMetabaseAPIClient(srv.URL, "test-key")
+	info, err := client.FetchServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("FetchServerInfo failed: %v", err)
+	}
+	if info.Version != (Version{50, 3, 0}) || !info.Enterprise || info.Edition() != "Enterprise" {
+		t.Errorf("unexpected server info %+v", info)
+	}
+	if !info.Features["sandboxes"] || info.Features["audit_app"] {
+		t.Errorf("expected only enabled token features, got %v", info.Features)
+	}
+	if client.ServerInfo != info {
+		t.Errorf("expected the server info to be cached on the client")
+	}
+}
+
+func TestRequire(t *testing.T) {
+	oss := &ServerInfo{Tag: "v0.49.1", Version: Version{49, 1, 0}, Features: map[string]bool{}}
+	ee := &ServerInfo{Tag: "v1.50.0", Version: Version{50, 0, 0}, Enterprise: true, Features: map[string]bool{"sandboxes": true}}
+
+	tests := []struct {
+		name    string
+		info    *ServerInfo
+		req     Requirement
+		wantErr string
+	}{
+		{"unknown server passes", nil, Requirement{Feature: "x", MinVersion: 99, Enterprise: true}, ""},
+		{"version satisfied", ee, Requirement{Feature: "x", MinVersion: 50}, ""},
+		{"version too old", oss, Requirement{Feature: "x", MinVersion: 50}, "x requires Metabase >= 50 (this instance runs v0.49.1 OSS)"},
+		{"enterprise on OSS", oss, Requirement{Feature: "x", MinVersion: 50, TokenFeature: "sandboxes"}, "x requires Metabase >= 50 / Enterprise (this instance runs v0.49.1 OSS)"},
+		{"token feature enabled", ee, Requirement{Feature: "x", TokenFeature: "sandboxes"}, ""},
+		{"token feature missing", ee, Requirement{Feature: "x", TokenFeature: "advanced_permissions"}, `x requires a license with the "advanced_permissions" feature (this instance runs v1.50.0 Enterprise)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MetabaseAPIClient{ServerInfo: tt.info}
+			err := client.Require(tt.req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var unsupported *UnsupportedError
+			if !errors.As(err, &unsupported) {
+				t.Fatalf("expected UnsupportedError, got %v", err)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}